@@ -7,7 +7,9 @@ create table users
     nickname citext collate "C" primary key not null,
     fullname text                           not null,
     about    text,
-    email    citext unique                  not null
+    email    citext unique                  not null,
+    karma    integer                        not null default 0,
+    version  integer                        not null default 1
 );
 
 drop table if exists forums cascade;
@@ -19,6 +21,10 @@ create table forums
     username citext    not null,
     posts    integer   not null default 0,
     threads  integer   not null default 0,
+    last_post_id     bigint,
+    last_post_author citext,
+    last_post_thread bigint,
+    last_post_at     timestamp with time zone,
     foreign key (username) references users (nickname)
 );
 
@@ -43,6 +49,17 @@ create table threads
     slug    citext unique,
     created timestamp with time zone default now(),
     forum   citext  not null,
+    tags    text[]  not null         default '{}',
+    -- last post activity, kept up to date by CreatePosts
+    posts            integer not null default 0,
+    last_post_at     timestamp with time zone,
+    last_post_id     bigint,
+    last_post_author citext,
+    -- hot ranking, derived from the votes and the last post activity by thread_hot_score_set
+    hot_score        double precision not null default 0,
+    version          integer          not null default 1,
+    title_tsv   tsvector generated always as (to_tsvector('english', title)) stored,
+    message_tsv tsvector generated always as (to_tsvector('english', message)) stored,
     foreign key (author) references users (nickname),
     foreign key (forum) references forums (slug)
 );
@@ -54,8 +71,8 @@ create table votes
     username citext not null,
     voice    int    not null,
     unique (thread, username),
-    foreign key (thread) references threads (id),
-    foreign key (username) references users (nickname)
+    constraint votes_thread_fkey foreign key (thread) references threads (id),
+    constraint votes_username_fkey foreign key (username) references users (nickname)
 );
 
 
@@ -66,16 +83,157 @@ create table if not exists posts
     parent    bigint,
     author    citext  not null,
     message   text    not null,
+    -- the message rendered from markdown, kept along with it so that it is rendered once per edit
+    message_html text not null default '',
     is_edited boolean not null         default false,
     forum     citext  not null,
     thread    bigint,
     created   timestamp with time zone default now(),
     way       bigint[],
+    votes     integer not null         default 0,
+    version   integer not null         default 1,
+    mentions  text[]  not null         default '{}',
+    quotes    bigint[] not null        default '{}',
+    message_tsv tsvector generated always as (to_tsvector('english', message)) stored,
     foreign key (author) references users (nickname),
     foreign key (forum) references forums (slug),
     foreign key (thread) references threads (id)
 );
 
+-- users mentioned in posts, posts.mentions holds the same nicknames for reading posts
+drop table if exists mentions cascade;
+create table mentions
+(
+    post     bigint not null references posts (id),
+    username citext not null references users (nickname),
+    unique (post, username)
+);
+
+-- backlinks of quoted posts, posts.quotes keeps the order the quoting post gave
+drop table if exists quotes cascade;
+create table quotes
+(
+    post   bigint not null references posts (id),
+    quoted bigint not null references posts (id),
+    unique (post, quoted)
+);
+
+drop table if exists post_votes cascade;
+create table post_votes
+(
+    post     bigint not null,
+    username citext not null,
+    voice    int    not null,
+    unique (post, username),
+    constraint post_votes_post_fkey foreign key (post) references posts (id),
+    constraint post_votes_username_fkey foreign key (username) references users (nickname)
+);
+
+drop table if exists idempotency_keys cascade;
+create table idempotency_keys
+(
+    scope        text                     not null,
+    key          text                     not null,
+    fingerprint  bytea                    not null,
+    status       integer,
+    content_type text,
+    etag         text,
+    location     text,
+    link         text,
+    response     bytea,
+    created      timestamp with time zone not null default now(),
+    primary key (scope, key)
+);
+
+drop table if exists webhooks cascade;
+create table webhooks
+(
+    id      bigserial primary key,
+    url     text                     not null,
+    secret  text                     not null,
+    events  text[]                   not null,
+    active  boolean                  not null default true,
+    created timestamp with time zone not null default now()
+);
+
+-- events are written in the transactions of the changes they describe
+drop table if exists outbox cascade;
+create table outbox
+(
+    id      bigserial primary key,
+    type    text                     not null,
+    payload jsonb                    not null,
+    created timestamp with time zone not null default now()
+);
+
+-- status is pending until the event is delivered, dead deliveries ran out of attempts
+drop table if exists webhook_deliveries cascade;
+create table webhook_deliveries
+(
+    id              bigserial primary key,
+    webhook         bigint                   not null references webhooks (id) on delete cascade,
+    event           bigint                   not null references outbox (id),
+    status          text                     not null default 'pending',
+    attempts        integer                  not null default 0,
+    next_attempt    timestamp with time zone not null default now(),
+    last_error      text,
+    response_status integer,
+    created         timestamp with time zone not null default now(),
+    delivered       timestamp with time zone
+);
+
+-- users are subscribed to threads they create or post in, inactive subscriptions
+-- are the ones they left, so posting again does not bring them back
+drop table if exists thread_subscriptions cascade;
+create table thread_subscriptions
+(
+    thread   bigint                   not null references threads (id) on delete cascade,
+    username citext                   not null references users (nickname),
+    active   boolean                  not null default true,
+    created  timestamp with time zone not null default now(),
+    unique (username, thread)
+);
+
+-- posts of every thread of a watched forum go to the feed of the user
+drop table if exists forum_subscriptions cascade;
+create table forum_subscriptions
+(
+    forum    citext                   not null references forums (slug),
+    username citext                   not null references users (nickname),
+    created  timestamp with time zone not null default now(),
+    unique (username, forum)
+);
+
+-- the last post of the feed the user has seen
+drop table if exists feed_visits cascade;
+create table feed_visits
+(
+    username  citext primary key references users (nickname),
+    last_post bigint not null
+);
+
+-- posts up to last_read are read by the user, threads without a marker are unread as a whole
+drop table if exists read_markers cascade;
+create table read_markers
+(
+    thread    bigint not null references threads (id) on delete cascade,
+    username  citext not null references users (nickname),
+    last_read bigint not null,
+    unique (username, thread)
+);
+
+-- notifications point at posts, so they show the post where it is now after merges and splits
+drop table if exists notifications cascade;
+create table notifications
+(
+    id       bigserial primary key,
+    username citext                   not null references users (nickname),
+    type     text                     not null,
+    post     bigint                   not null references posts (id),
+    read     boolean                  not null default false,
+    created  timestamp with time zone not null default now()
+);
+
 create index user_nickname_index on users using hash (nickname);
 create index user_email_index on users using hash (email);
 
@@ -84,16 +242,111 @@ create index forum_slug_index on forums using hash (slug);
 create index thread_slug_index on threads using hash (slug) where slug is not null;
 create index thread_forum_index on threads using hash (forum);
 create index thread_fcreated_index on threads (forum, created);
+create index thread_tags_index on threads using gin (tags);
+create index thread_fhot_index on threads (forum, hot_score, id);
+create index thread_fvotes_index on threads (forum, votes, id);
+create index thread_factive_index on threads (forum, last_post_at, id);
+create index thread_hot_index on threads (hot_score, id);
+create index thread_title_tsv_index on threads using gin (title_tsv);
+create index thread_message_tsv_index on threads using gin (message_tsv);
+-- other search languages are indexed by expressions, the search builds the same ones
+create index thread_title_russian_index on threads using gin (to_tsvector('russian', title));
+create index thread_message_russian_index on threads using gin (to_tsvector('russian', message));
+create index thread_title_simple_index on threads using gin (to_tsvector('simple', title));
+create index thread_message_simple_index on threads using gin (to_tsvector('simple', message));
 
 create index fu_user_index on f_u using hash (u);
 
+create index idempotency_keys_created_index on idempotency_keys (created);
+
+create index webhook_deliveries_due_index on webhook_deliveries (next_attempt) where status = 'pending';
+create index webhook_deliveries_webhook_index on webhook_deliveries (webhook, id);
+create index webhook_deliveries_event_index on webhook_deliveries (event);
+create index thread_subscriptions_thread_index on thread_subscriptions (thread) where active;
+create index notifications_user_index on notifications (username, id);
+create index notifications_unread_index on notifications (username) where not read;
+
 create index votes_index on votes (thread, username);
 
 create index post_forum_index on posts (forum);
+create index post_forum_id_index on posts (forum, id);
 create index post_user_index on posts (author);
-create index post_thread_index on posts (thread);
+create index post_thread_index on posts (thread, id);
 create index posts_way_index on posts (way);
 create index posts_way_second_index on posts ((way[2]));
+create index posts_message_tsv_index on posts using gin (message_tsv);
+create index posts_message_russian_index on posts using gin (to_tsvector('russian', message));
+create index posts_message_simple_index on posts using gin (to_tsvector('simple', message));
+create index posts_thread_parent_index on posts (thread, parent);
+create index mentions_user_index on mentions (username, post);
+create index quotes_quoted_index on quotes (quoted, post);
+
+--- VERSIONS
+-- every change of what users edit is a new version of the resource, entity tags are built from it.
+-- Counters, rankings and votes kept by triggers change all the time and leave the version as is,
+-- so that If-Match edits of busy resources don't fail.
+create or replace function bump_version()
+    returns trigger as
+$$
+begin
+    new.version := old.version + 1;
+    return new;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists user_version_bump on users;
+create trigger user_version_bump
+    before update
+    on users
+    for each row
+    when ((old.fullname, old.about, old.email) is distinct from (new.fullname, new.about, new.email))
+execute procedure bump_version();
+
+drop trigger if exists thread_version_bump on threads;
+create trigger thread_version_bump
+    before update
+    on threads
+    for each row
+    when ((old.title, old.message, old.slug, old.tags) is distinct from (new.title, new.message, new.slug, new.tags))
+execute procedure bump_version();
+
+-- posts moved by split and merge are changed by users too
+drop trigger if exists post_version_bump on posts;
+create trigger post_version_bump
+    before update
+    on posts
+    for each row
+    when ((old.message, old.thread) is distinct from (new.message, new.thread))
+execute procedure bump_version();
+
+--- THREAD RANKING
+-- engagement is votes plus posts, every 12.5 hours of newer activity outweigh 10 times more engagement
+create or replace function thread_hot_score(votes integer, posts integer, active_at timestamp with time zone)
+    returns double precision as
+$$
+select sign((votes + posts)::double precision) * log(greatest(abs(votes + posts), 1)::double precision) +
+       extract(epoch from active_at)::double precision / 45000;
+$$
+    language sql immutable;
+
+create or replace function update_thread_hot_score()
+    returns trigger as
+$$
+begin
+    new.last_post_at := coalesce(new.last_post_at, new.created, now());
+    new.hot_score := thread_hot_score(new.votes, new.posts, new.last_post_at);
+    return new;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists thread_hot_score_set on threads;
+create trigger thread_hot_score_set
+    before insert or update of votes, posts, last_post_at
+    on threads
+    for each row
+execute procedure update_thread_hot_score();
 
 --- NEW THREAD
 create or replace function new_thread_update()
@@ -103,6 +356,9 @@ begin
     insert into f_u(f, u)
     select new.forum, new.author
     on conflict do nothing;
+    insert into thread_subscriptions(thread, username)
+    select new.id, new.author
+    on conflict do nothing;
     update forums
     set threads = threads + 1
     where slug = new.forum;
@@ -149,6 +405,10 @@ declare
     parent_way    bigint[];
     parent_thread bigint;
 begin
+    -- bulk inserts come with ways built by the application
+    if (new.way is not null) then
+        return new;
+    end if;
     if (new.parent = 0) then
         new.way = array [0,new.id];
     else
@@ -177,10 +437,16 @@ execute procedure update_post_ways();
 create or replace function new_vote_update_thread()
     returns trigger as
 $$
+declare
+    thread_author citext;
 begin
     update threads
     set votes = votes + new.voice
-    where id = new.thread;
+    where id = new.thread
+    returning author into thread_author;
+    update users
+    set karma = karma + new.voice
+    where nickname = thread_author;
     return null;
 end;
 $$
@@ -197,10 +463,16 @@ execute procedure new_vote_update_thread();
 create or replace function updated_vote_update_thread()
     returns trigger as
 $$
+declare
+    thread_author citext;
 begin
     update threads
     set votes = (votes + new.voice - old.voice)
-    where id = new.thread;
+    where id = new.thread
+    returning author into thread_author;
+    update users
+    set karma = karma + new.voice - old.voice
+    where nickname = thread_author;
     return null;
 end;
 $$
@@ -211,4 +483,190 @@ create trigger vote_updated
     after update
     on votes
     for each row
-execute procedure updated_vote_update_thread();
\ No newline at end of file
+execute procedure updated_vote_update_thread();
+
+
+create or replace function deleted_vote_update_thread()
+    returns trigger as
+$$
+declare
+    thread_author citext;
+begin
+    update threads
+    set votes = votes - old.voice
+    where id = old.thread
+    returning author into thread_author;
+    update users
+    set karma = karma - old.voice
+    where nickname = thread_author;
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists vote_deleted on votes;
+create trigger vote_deleted
+    after delete
+    on votes
+    for each row
+execute procedure deleted_vote_update_thread();
+
+--- POST VOTES
+create or replace function new_post_vote_update_post()
+    returns trigger as
+$$
+declare
+    post_author citext;
+begin
+    update posts
+    set votes = votes + new.voice
+    where id = new.post
+    returning author into post_author;
+    update users
+    set karma = karma + new.voice
+    where nickname = post_author;
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists new_post_vote_set on post_votes;
+create trigger new_post_vote_set
+    after insert
+    on post_votes
+    for each row
+execute procedure new_post_vote_update_post();
+
+
+create or replace function updated_post_vote_update_post()
+    returns trigger as
+$$
+declare
+    post_author citext;
+begin
+    update posts
+    set votes = votes + new.voice - old.voice
+    where id = new.post
+    returning author into post_author;
+    update users
+    set karma = karma + new.voice - old.voice
+    where nickname = post_author;
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists post_vote_updated on post_votes;
+create trigger post_vote_updated
+    after update
+    on post_votes
+    for each row
+execute procedure updated_post_vote_update_post();
+
+
+create or replace function deleted_post_vote_update_post()
+    returns trigger as
+$$
+declare
+    post_author citext;
+begin
+    update posts
+    set votes = votes - old.voice
+    where id = old.post
+    returning author into post_author;
+    update users
+    set karma = karma - old.voice
+    where nickname = post_author;
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists post_vote_deleted on post_votes;
+create trigger post_vote_deleted
+    after delete
+    on post_votes
+    for each row
+execute procedure deleted_post_vote_update_post();
+
+--- EVENTS
+-- changes are announced on commit to every server instance listening to the channel,
+-- payloads only point at the changed rows
+create or replace function notify_post_event()
+    returns trigger as
+$$
+declare
+    event_type text;
+begin
+    if (tg_op = 'INSERT') then
+        event_type := 'post.created';
+    elsif (new.message is distinct from old.message) then
+        event_type := 'post.edited';
+    elsif (new.votes is distinct from old.votes) then
+        event_type := 'post.voted';
+    else
+        return null;
+    end if;
+    perform pg_notify('forum_events', json_build_object('type', event_type, 'id', new.id, 'thread', new.thread,
+                                                        'forum', new.forum)::text);
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists post_event on posts;
+create trigger post_event
+    after insert or update of message, votes
+    on posts
+    for each row
+execute procedure notify_post_event();
+
+create or replace function notify_thread_event()
+    returns trigger as
+$$
+declare
+    event_type text;
+begin
+    if (new.title is distinct from old.title or new.message is distinct from old.message) then
+        event_type := 'thread.updated';
+    elsif (new.votes is distinct from old.votes) then
+        event_type := 'thread.voted';
+    else
+        return null;
+    end if;
+    perform pg_notify('forum_events', json_build_object('type', event_type, 'id', new.id, 'thread', new.id,
+                                                        'forum', new.forum)::text);
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists thread_event on threads;
+create trigger thread_event
+    after update of title, message, votes
+    on threads
+    for each row
+execute procedure notify_thread_event();
+
+--- WEBHOOKS
+-- every active webhook subscribed to the event type gets its delivery in the transaction of the event
+create or replace function outbox_fan_out()
+    returns trigger as
+$$
+begin
+    insert into webhook_deliveries(webhook, event)
+    select id, new.id
+    from webhooks
+    where active
+      and new.type = any (events);
+    return null;
+end;
+$$
+    language 'plpgsql';
+
+drop trigger if exists outbox_event_created on outbox;
+create trigger outbox_event_created
+    after insert
+    on outbox
+    for each row
+execute procedure outbox_fan_out();
\ No newline at end of file
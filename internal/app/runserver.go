@@ -6,19 +6,34 @@ import (
 	"github.com/jackc/pgx"
 	log "github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+	"os"
+	"technopark-dbms/internal/pkg/cache"
 	forumDelivery "technopark-dbms/internal/pkg/forum/delivery"
 	forumDBUsecase "technopark-dbms/internal/pkg/forum/usecase"
 	"technopark-dbms/internal/pkg/middlewares"
+	notificationDelivery "technopark-dbms/internal/pkg/notification/delivery"
+	notificationDBUsecase "technopark-dbms/internal/pkg/notification/usecase"
 	postDelivery "technopark-dbms/internal/pkg/post/delivery"
 	postDBUsecase "technopark-dbms/internal/pkg/post/usecase"
+	searchDelivery "technopark-dbms/internal/pkg/search/delivery"
+	searchDBUsecase "technopark-dbms/internal/pkg/search/usecase"
 	serviceDelivery "technopark-dbms/internal/pkg/service/delivery"
 	serviceDBUsecase "technopark-dbms/internal/pkg/service/usecase"
+	streamDelivery "technopark-dbms/internal/pkg/stream/delivery"
+	streamDBUsecase "technopark-dbms/internal/pkg/stream/usecase"
+	subscriptionDelivery "technopark-dbms/internal/pkg/subscription/delivery"
+	subscriptionDBUsecase "technopark-dbms/internal/pkg/subscription/usecase"
 	threadDelivery "technopark-dbms/internal/pkg/thread/delivery"
 	threadDBUsecase "technopark-dbms/internal/pkg/thread/usecase"
 	userDelivery "technopark-dbms/internal/pkg/user/delivery"
 	userDBUsecase "technopark-dbms/internal/pkg/user/usecase"
+	webhookDelivery "technopark-dbms/internal/pkg/webhook/delivery"
+	webhookDBUsecase "technopark-dbms/internal/pkg/webhook/usecase"
 )
 
+// cacheEnv selects the entity cache backend, see cache.New
+const cacheEnv = "DBMS_CACHE"
+
 // REQUIRES POSTGRES DRIVER IN IMPORT
 func getPostgres() *pgx.ConnPool {
 	conf := pgx.ConnConfig{
@@ -45,21 +60,38 @@ func RunServer(addr string) {
 	r := router.New()
 
 	db := getPostgres()
+	c, err := cache.New(os.Getenv(cacheEnv))
+	if err != nil {
+		log.WithError(err).Fatal("cache configuration error")
+	}
 
-	serviceUsecase := serviceDBUsecase.NewServiceUsecase(db)
-	userUsecase := userDBUsecase.NewUserUsecase(db)
-	threadUsecase := threadDBUsecase.NewThreadUsecase(db, userUsecase)
-	forumUsecase := forumDBUsecase.NewForumUsecase(db, userUsecase, threadUsecase)
-	postUsecase := postDBUsecase.NewPostUsecase(db, userUsecase, forumUsecase, threadUsecase)
+	serviceUsecase := serviceDBUsecase.NewServiceUsecase(db, c)
+	userUsecase := userDBUsecase.NewUserUsecase(db, c)
+	threadUsecase := threadDBUsecase.NewThreadUsecase(db, userUsecase, c)
+	forumUsecase := forumDBUsecase.NewForumUsecase(db, userUsecase, threadUsecase, c)
+	postUsecase := postDBUsecase.NewPostUsecase(db, userUsecase, forumUsecase, threadUsecase, c)
+	searchUsecase := searchDBUsecase.NewSearchUsecase(db)
+	streamUsecase := streamDBUsecase.NewStreamUsecase(db, threadUsecase, forumUsecase)
+	webhookUsecase := webhookDBUsecase.NewWebhookUsecase(db)
+	notificationUsecase := notificationDBUsecase.NewNotificationUsecase(db, userUsecase)
+	subscriptionUsecase := subscriptionDBUsecase.NewSubscriptionUsecase(db, userUsecase, threadUsecase, forumUsecase)
 
 	forumDelivery.NewForumHandler(r, forumUsecase)
+	notificationDelivery.NewNotificationHandler(r, notificationUsecase, postUsecase)
 	postDelivery.NewPostHandler(r, postUsecase)
-	threadDelivery.NewThreadHandler(r, threadUsecase)
+	threadDelivery.NewThreadHandler(r, threadUsecase, postUsecase)
 	serviceDelivery.NewServiceHandler(r, serviceUsecase)
+	searchDelivery.NewSearchHandler(r, searchUsecase, postUsecase)
+	streamDelivery.NewStreamHandler(r, streamUsecase, postUsecase)
+	subscriptionDelivery.NewSubscriptionHandler(r, subscriptionUsecase, postUsecase)
 	userDelivery.NewUserHandler(r, userUsecase)
+	webhookDelivery.NewWebhookHandler(r, webhookUsecase)
+
+	go webhookDBUsecase.NewDispatcher(db).Run()
+	go middlewares.ExpireIdempotencyKeys(db)
 
 	log.Println("Listening at: ", addr)
-	err := fasthttp.ListenAndServe(addr, middlewares.Logging(r.Handler))
+	err = fasthttp.ListenAndServe(addr, middlewares.Logging(middlewares.Idempotency(db, r.Handler)))
 	if err != nil {
 		log.Println(fmt.Sprint("Server error: ", err))
 	}
@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+// Backend stores encoded entities, a nil value with nil error is a miss
+type Backend interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(keys ...string) error
+	Flush() error
+	// Len is the number of stored entries, negative if the backend does not know it
+	Len() int
+}
+
+// Cache is a read-through cache of entities in front of the database.
+// Backend errors are logged and treated as misses, so the database stays the source of truth.
+// Every method of a nil Cache is a no-op, it is used when caching is turned off.
+type Cache struct {
+	backend Backend
+	name    string
+	ttl     time.Duration
+
+	hits          int64
+	misses        int64
+	invalidations int64
+	errors        int64
+}
+
+func NewCache(backend Backend, name string, ttl time.Duration) *Cache {
+	return &Cache{
+		backend: backend,
+		name:    name,
+		ttl:     ttl,
+	}
+}
+
+// New builds the cache from its configuration: empty or "lru" for the in-process LRU,
+// "redis://host:port/db" for a Redis protocol server and "off" to disable caching
+func New(config string) (*Cache, error) {
+	switch {
+	case config == "" || config == "lru":
+		return NewCache(NewLRU(constants.CacheSize), "lru", constants.CacheTTL), nil
+	case config == "off":
+		return nil, nil
+	case strings.HasPrefix(config, "redis://"):
+		u, err := url.Parse(config)
+		if err != nil {
+			return nil, err
+		}
+		db := 0
+		if path := strings.Trim(u.Path, "/"); path != "" {
+			if db, err = strconv.Atoi(path); err != nil {
+				return nil, fmt.Errorf("wrong redis database %q", path)
+			}
+		}
+		password, _ := u.User.Password()
+		return NewCache(NewRedis(u.Host, password, db), "redis", constants.CacheTTL), nil
+	}
+	return nil, fmt.Errorf("unknown cache backend %q", config)
+}
+
+// Load decodes the entity stored under the key into v and reports whether it was found
+func (c *Cache) Load(key string, v interface{}) bool {
+	if c == nil {
+		return false
+	}
+	value, err := c.backend.Get(key)
+	if err != nil {
+		c.fail(err, "cache get error")
+	}
+	if value == nil {
+		atomic.AddInt64(&c.misses, 1)
+		return false
+	}
+	if err = gob.NewDecoder(bytes.NewReader(value)).Decode(v); err != nil {
+		c.fail(err, "cache decode error")
+		atomic.AddInt64(&c.misses, 1)
+		return false
+	}
+	atomic.AddInt64(&c.hits, 1)
+	return true
+}
+
+// Store puts the entity under the key until it is invalidated or expires
+func (c *Cache) Store(key string, v interface{}) {
+	if c == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		c.fail(err, "cache encode error")
+		return
+	}
+	if err := c.backend.Set(key, buf.Bytes(), c.ttl); err != nil {
+		c.fail(err, "cache set error")
+	}
+}
+
+// Invalidate drops the entities changed by a write
+func (c *Cache) Invalidate(keys ...string) {
+	if c == nil || len(keys) == 0 {
+		return
+	}
+	atomic.AddInt64(&c.invalidations, int64(len(keys)))
+	if err := c.backend.Delete(keys...); err != nil {
+		c.fail(err, "cache delete error")
+	}
+}
+
+// Flush drops every entity, it follows the database being cleared
+func (c *Cache) Flush() {
+	if c == nil {
+		return
+	}
+	if err := c.backend.Flush(); err != nil {
+		c.fail(err, "cache flush error")
+	}
+}
+
+func (c *Cache) Stats() *domain.CacheStats {
+	if c == nil {
+		return &domain.CacheStats{Backend: "off"}
+	}
+	stats := &domain.CacheStats{
+		Backend:       c.name,
+		Hits:          atomic.LoadInt64(&c.hits),
+		Misses:        atomic.LoadInt64(&c.misses),
+		Invalidations: atomic.LoadInt64(&c.invalidations),
+		Errors:        atomic.LoadInt64(&c.errors),
+	}
+	if entries := c.backend.Len(); entries >= 0 {
+		stats.Entries = int64(entries)
+	}
+	if lookups := stats.Hits + stats.Misses; lookups != 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+func (c *Cache) fail(err error, message string) {
+	atomic.AddInt64(&c.errors, 1)
+	log.WithError(err).Error(message)
+}
+
+// ForumKey is case insensitive like the citext columns entities are looked up by, so are the other keys
+func ForumKey(slug string) string {
+	return "forum:" + strings.ToLower(slug)
+}
+
+func UserKey(nickname string) string {
+	return "user:" + strings.ToLower(nickname)
+}
+
+func ThreadKey(s utilities.SlugOrId) string {
+	if s.IsSlug {
+		return ThreadSlugKey(s.Slug)
+	}
+	return ThreadIDKey(s.ID)
+}
+
+func ThreadIDKey(id int32) string {
+	return "thread:" + strconv.FormatInt(int64(id), 10)
+}
+
+func ThreadSlugKey(slug string) string {
+	return "thread:slug:" + strings.ToLower(slug)
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU(t *testing.T) {
+	type step struct {
+		op    string
+		key   string
+		value string
+		ttl   time.Duration
+	}
+	set := func(key, value string) step { return step{op: "set", key: key, value: value, ttl: time.Minute} }
+	get := func(key string) step { return step{op: "get", key: key} }
+
+	tests := []struct {
+		name    string
+		steps   []step
+		want    map[string]string
+		wantLen int
+	}{
+		{
+			name:    "miss",
+			want:    map[string]string{"a": ""},
+			wantLen: 0,
+		},
+		{
+			name:    "hit",
+			steps:   []step{set("a", "1")},
+			want:    map[string]string{"a": "1"},
+			wantLen: 1,
+		},
+		{
+			name:    "overwrite",
+			steps:   []step{set("a", "1"), set("a", "2")},
+			want:    map[string]string{"a": "2"},
+			wantLen: 1,
+		},
+		{
+			name:    "least recently set is evicted",
+			steps:   []step{set("a", "1"), set("b", "2"), set("c", "3")},
+			want:    map[string]string{"a": "", "b": "2", "c": "3"},
+			wantLen: 2,
+		},
+		{
+			name:    "get keeps the entry",
+			steps:   []step{set("a", "1"), set("b", "2"), get("a"), set("c", "3")},
+			want:    map[string]string{"a": "1", "b": "", "c": "3"},
+			wantLen: 2,
+		},
+		{
+			name:    "overwrite keeps the entry",
+			steps:   []step{set("a", "1"), set("b", "2"), set("a", "3"), set("c", "4")},
+			want:    map[string]string{"a": "3", "b": "", "c": "4"},
+			wantLen: 2,
+		},
+		{
+			name:    "expired",
+			steps:   []step{{op: "set", key: "a", value: "1", ttl: -time.Second}, get("a")},
+			want:    map[string]string{"a": ""},
+			wantLen: 0,
+		},
+		{
+			name:    "delete",
+			steps:   []step{set("a", "1"), set("b", "2"), {op: "delete", key: "a"}, {op: "delete", key: "x"}},
+			want:    map[string]string{"a": "", "b": "2"},
+			wantLen: 1,
+		},
+		{
+			name:    "flush",
+			steps:   []step{set("a", "1"), set("b", "2"), {op: "flush"}, set("c", "3")},
+			want:    map[string]string{"a": "", "b": "", "c": "3"},
+			wantLen: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLRU(2)
+			for _, s := range test.steps {
+				switch s.op {
+				case "set":
+					_ = l.Set(s.key, []byte(s.value), s.ttl)
+				case "get":
+					_, _ = l.Get(s.key)
+				case "delete":
+					_ = l.Delete(s.key)
+				case "flush":
+					_ = l.Flush()
+				}
+			}
+			if got := l.Len(); got != test.wantLen {
+				t.Errorf("Len() = %d, want %d", got, test.wantLen)
+			}
+			for key, want := range test.want {
+				got, err := l.Get(key)
+				if err != nil {
+					t.Fatalf("Get(%q) error = %v", key, err)
+				}
+				if want == "" && got != nil {
+					t.Errorf("Get(%q) = %q, want a miss", key, got)
+				} else if string(got) != want {
+					t.Errorf("Get(%q) = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"technopark-dbms/internal/pkg/constants"
+	"time"
+)
+
+var ErrRedisReply = errors.New("unexpected redis reply")
+
+// Redis is a backend talking the Redis protocol to a server, it should own the database it is given
+// as a flush of the cache flushes the whole database
+type Redis struct {
+	addr     string
+	password string
+	db       int
+	conns    chan *redisConn
+}
+
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func NewRedis(addr string, password string, db int) *Redis {
+	return &Redis{
+		addr:     addr,
+		password: password,
+		db:       db,
+		conns:    make(chan *redisConn, constants.CacheRedisConns),
+	}
+}
+
+func (r *Redis) Get(key string) ([]byte, error) {
+	reply, err := r.do("GET", key)
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, ErrRedisReply
+	}
+	return value, nil
+}
+
+func (r *Redis) Set(key string, value []byte, ttl time.Duration) error {
+	_, err := r.do("SET", key, value, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	return err
+}
+
+func (r *Redis) Delete(keys ...string) error {
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, "DEL")
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	_, err := r.do(args...)
+	return err
+}
+
+func (r *Redis) Flush() error {
+	_, err := r.do("FLUSHDB")
+	return err
+}
+
+// Len is unknown, the database size is not worth a round trip on every stats request
+func (r *Redis) Len() int {
+	return -1
+}
+
+// do runs a command on a pooled connection, a connection that failed is closed instead of going back to the pool
+func (r *Redis) do(args ...interface{}) (interface{}, error) {
+	c, err := r.conn()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := c.do(args...)
+	if _, isReply := err.(redisError); err != nil && !isReply {
+		_ = c.conn.Close()
+		return nil, err
+	}
+	select {
+	case r.conns <- c:
+	default:
+		_ = c.conn.Close()
+	}
+	return reply, err
+}
+
+func (r *Redis) conn() (*redisConn, error) {
+	select {
+	case c := <-r.conns:
+		return c, nil
+	default:
+	}
+	conn, err := net.DialTimeout("tcp", r.addr, constants.CacheRedisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+	if r.password != "" {
+		if _, err = c.do("AUTH", r.password); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	if r.db != 0 {
+		if _, err = c.do("SELECT", strconv.Itoa(r.db)); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *redisConn) do(args ...interface{}) (interface{}, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(constants.CacheRedisTimeout)); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(c.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case string:
+			fmt.Fprintf(c.writer, "$%d\r\n%s\r\n", len(arg), arg)
+		case []byte:
+			fmt.Fprintf(c.writer, "$%d\r\n", len(arg))
+			_, _ = c.writer.Write(arg)
+			_, _ = c.writer.WriteString("\r\n")
+		}
+	}
+	if err := c.writer.Flush(); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read parses a reply, bulk strings come as []byte, integers as int64 and arrays as []interface{}
+func (c *redisConn) read() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, ErrRedisReply
+	}
+	kind, line := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return line, nil
+	case '-':
+		return nil, redisError(line)
+	case ':':
+		return strconv.ParseInt(line, 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		value := make([]byte, length+2)
+		if _, err = io.ReadFull(c.reader, value); err != nil {
+			return nil, err
+		}
+		return value[:length], nil
+	case '*':
+		length, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		values := make([]interface{}, length)
+		for i := range values {
+			if values[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, ErrRedisReply
+}
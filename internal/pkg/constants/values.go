@@ -3,3 +3,81 @@ package constants
 import "time"
 
 const TimeLayout = time.RFC3339Nano
+
+// SearchLanguage is the text search configuration the tsvector columns are built with
+const SearchLanguage = "english"
+
+// SearchLanguages are the text search configurations posts and threads are indexed for, the rest are not searchable
+const SearchLanguages = SearchLanguage + " russian simple"
+
+// MaxLimit is the largest page size listings accept
+const MaxLimit = 10000
+
+// IdempotencyTTL is how long responses of requests with an idempotency key are replayed
+const IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyExpiryInterval is how often expired idempotency keys are removed
+const IdempotencyExpiryInterval = time.Hour
+
+// QuoteExcerptLength is the most characters of a quoted message shown in the quoting post
+const QuoteExcerptLength = 200
+
+// CopyPostsBatch is the smallest posts batch loaded with COPY instead of a single insert
+const CopyPostsBatch = 1000
+
+// CacheSize is the most entities the in-process cache keeps
+const CacheSize = 10000
+
+// CacheTTL bounds how long a cached entity lives without being invalidated
+const CacheTTL = time.Minute
+
+// CacheRedisConns is the most idle connections kept to a Redis cache server
+const CacheRedisConns = 16
+
+// CacheRedisTimeout bounds a round trip to a Redis cache server, a slow cache is a miss
+const CacheRedisTimeout = 100 * time.Millisecond
+
+// StreamChannel is the notification channel database triggers announce committed changes on
+const StreamChannel = "forum_events"
+
+// StreamBuffer is how many events a stream subscriber may lag behind before it is dropped
+const StreamBuffer = 256
+
+// StreamBacklog is the most missed posts replayed to a resuming subscriber
+const StreamBacklog = 1000
+
+// StreamKeepAlive is the interval of comments keeping idle streams open
+const StreamKeepAlive = 15 * time.Second
+
+// StreamReconnectDelay is the pause before listening again after the listening connection fails
+const StreamReconnectDelay = time.Second
+
+// StreamQueueLimit is the most notifications waiting to be loaded, the stream restarts when the loading lags further
+const StreamQueueLimit = 100000
+
+// WebhookBatch is the most deliveries a dispatcher claims at once
+const WebhookBatch = 16
+
+// WebhookPollInterval is the pause of an idle dispatcher before it looks for due deliveries again
+const WebhookPollInterval = time.Second
+
+// WebhookTimeout bounds a delivery request, a delivery is claimed for this long
+const WebhookTimeout = 10 * time.Second
+
+// WebhookMaxAttempts is how many times a delivery is tried before it goes to the dead letters
+const WebhookMaxAttempts = 8
+
+// WebhookRetryBase is the delay before the first retry, it doubles with every failed attempt up to WebhookRetryMax
+const WebhookRetryBase = 10 * time.Second
+
+// WebhookRetryMax caps the delay between retries of a delivery
+const WebhookRetryMax = time.Hour
+
+// WebhookConcurrency is the most webhooks a dispatcher sends deliveries to at once
+const WebhookConcurrency = 32
+
+// WebhookRetention is how long delivered and dead deliveries are kept to be listed and replayed
+const WebhookRetention = 7 * 24 * time.Hour
+
+// WebhookPruneInterval is how often deliveries past the retention and their events are removed
+const WebhookPruneInterval = time.Hour
@@ -6,20 +6,30 @@ import (
 )
 
 type Forum struct {
-	Title   string `json:"title"`
-	User    string `json:"user"`
-	Slug    string `json:"slug"`
+	Title   string `json:"title" validate:"required,max=256"`
+	User    string `json:"user" validate:"required,nickname"`
+	Slug    string `json:"slug" validate:"required,slug"`
 	Posts   int64  `json:"posts,omitempty"`
 	Threads int64  `json:"threads,omitempty"`
+
+	LastPostAt     *strfmt.DateTime `json:"lastPostAt,omitempty"`
+	LastPostID     int64            `json:"lastPostId,omitempty"`
+	LastPostAuthor string           `json:"lastPostAuthor,omitempty"`
+	LastPostThread int32            `json:"lastPostThread,omitempty"`
 }
 
+//easyjson:json
+type ForumArray []Forum
+
 type ForumUsecase interface {
 	CreateForum(f Forum) (*Forum, error)
 	ForumExists(slug string) (bool, error)
 	GetForumDetails(slug string) (*Forum, error)
 	CreateThread(forumSlug string, t Thread) (*Thread, error)
-	GetUsers(forumSlug string, params utilities.ArrayOutParams) (UserArray, error)
-	GetThreads(forumSlug string, params utilities.ArrayOutParams) (ThreadArray, error)
+	GetUsers(forumSlug string, params utilities.ArrayOutParams) (UserArray, *utilities.Page, error)
+	GetThreads(forumSlug string, viewer string, params utilities.ArrayOutParams) (ThreadArray, *utilities.Page, error)
+	MarkForumRead(slug string, viewer string) (*Forum, error)
+	GetTags(forumSlug string, params utilities.ArrayOutParams) (TagCountArray, *utilities.Page, error)
 }
 
 //easyjson:json
@@ -28,17 +38,41 @@ type ThreadArray []Thread
 type Post struct {
 	ID       int64           `json:"id"`
 	Parent   int64           `json:"parent,omitempty"`
-	Author   string          `json:"author,omitempty"`
-	Message  string          `json:"message,omitempty"`
+	Author   string          `json:"author,omitempty" validate:"required@create,nickname"`
+	Message  string          `json:"message,omitempty" validate:"required@create"`
 	IsEdited bool            `json:"isEdited,omitempty"`
 	Forum    string          `json:"forum,omitempty"`
 	Thread   int32           `json:"thread,omitempty"`
 	Created  strfmt.DateTime `json:"created,omitempty"`
+	Votes    int32           `json:"votes,omitempty"`
+	Mentions []string        `json:"mentions,omitempty"`
+	Quotes   []int64         `json:"quotes,omitempty" validate:"max=10"`
+	Version  int32           `json:"-"`
+
+	// quoted posts resolved from Quotes
+	Quoted []Quote `json:"quoted,omitempty"`
+
+	// the message rendered from Markdown, sent with render=html only
+	MessageHTML string `json:"messageHtml,omitempty"`
+
+	// posts cut off by a depth limit tell how many replies they have
+	RepliesCount int32     `json:"repliesCount,omitempty"`
+	HasMore      bool      `json:"hasMore,omitempty"`
+	Children     PostArray `json:"children,omitempty"`
 }
 
 //easyjson:json
 type PostArray []Post
 
+// Quote is a quoted post as it shows in the quoting one, it may come from any thread and forum
+type Quote struct {
+	ID      int64  `json:"id"`
+	Author  string `json:"author"`
+	Excerpt string `json:"excerpt"`
+	Thread  int32  `json:"thread"`
+	Forum   string `json:"forum"`
+}
+
 type PostFull struct {
 	Post   *Post   `json:"post"`
 	Forum  *Forum  `json:"forum,omitempty"`
@@ -46,10 +80,63 @@ type PostFull struct {
 	User   *User   `json:"author,omitempty"`
 }
 
+type PostLookup struct {
+	IDs     []int64  `json:"ids" validate:"required,max=100"`
+	Related []string `json:"related"`
+}
+
+// PostLookupResult has every related entity once, however many of the posts refer to it
+type PostLookupResult struct {
+	Posts   PostArray   `json:"posts"`
+	Users   UserArray   `json:"users,omitempty"`
+	Threads ThreadArray `json:"threads,omitempty"`
+	Forums  ForumArray  `json:"forums,omitempty"`
+	Missing []int64     `json:"missing,omitempty"`
+}
+
 type PostUsecase interface {
 	GetPostById(id int64) (*Post, error)
 	GetPostDetails(id int64, relatedUser bool, relatedForum bool, relatedThread bool) (*Post, *Forum, *Thread, *User, error)
+	LookupPosts(ids []int64, relatedUser bool, relatedForum bool, relatedThread bool) (*PostLookupResult, error)
+	GetPostReplies(id int64, params utilities.ArrayOutParams) (PostArray, *utilities.Page, error)
+	GetPostContext(id int64) (PostArray, error)
+	GetUserMentions(nickname string, params utilities.ArrayOutParams) (PostArray, *utilities.Page, error)
+	GetQuotedBy(id int64, params utilities.ArrayOutParams) (PostArray, *utilities.Page, error)
+	AttachHTML(posts []*Post) error
 	UpdatePostDetails(id int64, postUpdate Post) (*Post, error)
+	SplitPost(id int64, t Thread) (*Thread, error)
+	CreatePostVote(id int64, vote Vote) (*Post, error)
+}
+
+type SearchQuery struct {
+	Query  string
+	Type   string
+	Lang   string
+	Forum  string
+	Thread int32
+	Author string
+	Since  string
+	Until  string
+	Limit  int32
+	Cursor string
+}
+
+type SearchResult struct {
+	Type    string  `json:"type"`
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+	Post    *Post   `json:"post,omitempty"`
+	Thread  *Thread `json:"thread,omitempty"`
+}
+
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Next    string         `json:"next,omitempty"`
+	Prev    string         `json:"prev,omitempty"`
+}
+
+type SearchUsecase interface {
+	Search(q SearchQuery) (*SearchResults, error)
 }
 
 type Service struct {
@@ -59,25 +146,96 @@ type Service struct {
 	Post   int64 `json:"post"`
 }
 
+type CacheStats struct {
+	Backend       string  `json:"backend"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRatio      float64 `json:"hitRatio"`
+	Invalidations int64   `json:"invalidations"`
+	Errors        int64   `json:"errors"`
+	Entries       int64   `json:"entries,omitempty"`
+}
+
 type ServiceUsecase interface {
 	Clear() error
 	Status() (*Service, error)
+	CacheStats() *CacheStats
 }
 
 type Thread struct {
-	ID      int32           `json:"id"`
-	Title   string          `json:"title"`
-	Author  string          `json:"author"`
-	Forum   string          `json:"forum"`
-	Message string          `json:"message"`
-	Votes   int32           `json:"votes,omitempty"`
-	Slug    string          `json:"slug,omitempty"`
-	Created strfmt.DateTime `json:"created,omitempty"`
+	ID         int32            `json:"id"`
+	Title      string           `json:"title" validate:"required@create,max=256"`
+	Author     string           `json:"author" validate:"required@create,nickname"`
+	Forum      string           `json:"forum"`
+	Message    string           `json:"message" validate:"required@create"`
+	Votes      int32            `json:"votes,omitempty"`
+	Slug       string           `json:"slug,omitempty" validate:"slug"`
+	Created    strfmt.DateTime  `json:"created,omitempty"`
+	Tags       []string         `json:"tags,omitempty" validate:"max=16"`
+	Posts      int32            `json:"posts,omitempty"`
+	LastPostAt *strfmt.DateTime `json:"lastPostAt,omitempty"`
+	Hot        float64          `json:"hot,omitempty"`
+
+	LastPostID     int64  `json:"lastPostId,omitempty"`
+	LastPostAuthor string `json:"lastPostAuthor,omitempty"`
+	Version        int32  `json:"-"`
+
+	// read state of the viewer, left out for anonymous requests
+	UnreadCount       *int32 `json:"unreadCount,omitempty"`
+	FirstUnreadPostID int64  `json:"firstUnreadPostId,omitempty"`
+}
+
+// ReadMarker moves the read marker of the viewer up to the post, zero post means the whole thread
+type ReadMarker struct {
+	Post int64 `json:"post"`
+}
+
+type StreamEvent struct {
+	Type   string  `json:"type"`
+	Post   *Post   `json:"post,omitempty"`
+	Thread *Thread `json:"thread,omitempty"`
+}
+
+// StreamSubscription delivers events until Cancel is called, Events is closed if the subscriber is dropped
+//
+//easyjson:skip
+type StreamSubscription struct {
+	Events <-chan StreamEvent
+	Cancel func()
+}
+
+type StreamUsecase interface {
+	SubscribeThread(s utilities.SlugOrId, sinceID int64) (*StreamSubscription, error)
+	SubscribeForum(slug string, sinceID int64) (*StreamSubscription, error)
 }
 
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int32  `json:"count"`
+}
+
+//easyjson:json
+type TagCountArray []TagCount
+
+type ThreadMerge struct {
+	Source string `json:"source" validate:"required"`
+}
+
+// Vote is a voice of the user, a pointer so that an omitted voice is told apart from 0 retracting the vote
 type Vote struct {
-	Nickname string `json:"nickname"`
-	Voice    int32  `json:"voice"`
+	Nickname string `json:"nickname" validate:"required,nickname"`
+	Voice    *int32 `json:"voice" validate:"required,oneof=-1 0 1"`
+}
+
+//easyjson:json
+type VoteArray []Vote
+
+type ThreadVotes struct {
+	Upvotes     int32     `json:"upvotes"`
+	Downvotes   int32     `json:"downvotes"`
+	Score       int32     `json:"score"`
+	ViewerVoice int32     `json:"viewerVoice,omitempty"`
+	Voters      VoteArray `json:"voters"`
 }
 
 type ThreadUsecase interface {
@@ -85,15 +243,23 @@ type ThreadUsecase interface {
 	GetThreadDetails(s utilities.SlugOrId) (*Thread, error)
 	GetThreadIdAndForum(s utilities.SlugOrId) (*Thread, error)
 	UpdateThreadDetails(s utilities.SlugOrId, threadUpdate Thread) (*Thread, error)
-	GetThreadPosts(s utilities.SlugOrId, params utilities.ArrayOutParams) (PostArray, error)
+	GetThreadPosts(s utilities.SlugOrId, params utilities.ArrayOutParams) (PostArray, *utilities.Page, error)
 	CreateThreadVote(s utilities.SlugOrId, vote Vote) (*Thread, error)
+	MergeThreads(target utilities.SlugOrId, source utilities.SlugOrId) (*Thread, error)
+	GetThreadsByTag(tag string, params utilities.ArrayOutParams) (ThreadArray, *utilities.Page, error)
+	GetThreadVotes(s utilities.SlugOrId, viewer string, params utilities.ArrayOutParams) (*ThreadVotes, *utilities.Page, error)
+	GetTrendingThreads(params utilities.ArrayOutParams) (ThreadArray, *utilities.Page, error)
+	MarkThreadRead(s utilities.SlugOrId, viewer string, postID int64) (*Thread, error)
+	SetUnread(viewer string, threads ...*Thread) error
 }
 
 type User struct {
-	Nickname string `json:"nickname,omitempty"`
-	Fullname string `json:"fullname,omitempty"`
+	Nickname string `json:"nickname,omitempty" validate:"nickname"`
+	Fullname string `json:"fullname,omitempty" validate:"required@create,max=256"`
 	About    string `json:"about,omitempty"`
-	Email    string `json:"email,omitempty"`
+	Email    string `json:"email,omitempty" validate:"required@create,email"`
+	Karma    int32  `json:"karma"`
+	Version  int32  `json:"-"`
 }
 
 //easyjson:json
@@ -107,6 +273,125 @@ type UserUsecase interface {
 	UserExists(nickname string, email string) (bool, error)
 }
 
+// VoteCast is the payload of vote events, the target is either a thread or a post
+type VoteCast struct {
+	Nickname string `json:"nickname"`
+	Voice    int32  `json:"voice"`
+	Thread   int32  `json:"thread,omitempty"`
+	Post     int64  `json:"post,omitempty"`
+	Votes    int32  `json:"votes"`
+}
+
+type Webhook struct {
+	ID      int64           `json:"id"`
+	URL     string          `json:"url" validate:"required,max=2048,url"`
+	Secret  string          `json:"secret,omitempty" validate:"max=256"`
+	Events  []string        `json:"events" validate:"required"`
+	Active  bool            `json:"active"`
+	Created strfmt.DateTime `json:"created"`
+}
+
+//easyjson:json
+type WebhookArray []Webhook
+
+type WebhookDelivery struct {
+	ID             int64            `json:"id"`
+	Webhook        int64            `json:"webhook"`
+	Event          int64            `json:"event"`
+	Type           string           `json:"type"`
+	Status         string           `json:"status"`
+	Attempts       int32            `json:"attempts"`
+	NextAttempt    strfmt.DateTime  `json:"nextAttempt"`
+	LastError      string           `json:"lastError,omitempty"`
+	ResponseStatus int32            `json:"responseStatus,omitempty"`
+	Created        strfmt.DateTime  `json:"created"`
+	Delivered      *strfmt.DateTime `json:"delivered,omitempty"`
+}
+
+//easyjson:json
+type WebhookDeliveryArray []WebhookDelivery
+
+type WebhookUsecase interface {
+	CreateWebhook(w Webhook) (*Webhook, error)
+	GetWebhooks() (WebhookArray, error)
+	DeleteWebhook(id int64) error
+	GetDeliveries(webhookID int64, status string, params utilities.ArrayOutParams) (WebhookDeliveryArray, *utilities.Page, error)
+	ReplayDelivery(id int64) (*WebhookDelivery, error)
+}
+
+type Subscriber struct {
+	Nickname string `json:"nickname" validate:"required,nickname"`
+}
+
+type Subscriptions struct {
+	Forums  ForumArray  `json:"forums"`
+	Threads ThreadArray `json:"threads"`
+}
+
+// Feed holds posts of subscribed threads and watched forums, HasMore tells that newer posts wait for the next visit
+type Feed struct {
+	Posts   PostArray `json:"posts"`
+	HasMore bool      `json:"hasMore"`
+}
+
+// FeedVisit is the last feed post the user has seen, the feed starts after it
+type FeedVisit struct {
+	LastPost int64 `json:"lastPost" validate:"required"`
+}
+
+type SubscriptionUsecase interface {
+	SubscribeThread(nickname string, s utilities.SlugOrId) (*Thread, error)
+	UnsubscribeThread(nickname string, s utilities.SlugOrId) (*Thread, error)
+	SubscribeForum(nickname string, slug string) (*Forum, error)
+	UnsubscribeForum(nickname string, slug string) (*Forum, error)
+	GetSubscriptions(nickname string, limit int32) (*Subscriptions, error)
+	GetFeed(nickname string, params utilities.ArrayOutParams) (*Feed, error)
+	MarkFeedRead(nickname string, lastPost int64) (*FeedVisit, error)
+}
+
+type Notification struct {
+	ID      int64           `json:"id"`
+	Type    string          `json:"type"`
+	Post    *Post           `json:"post"`
+	Read    bool            `json:"read"`
+	Created strfmt.DateTime `json:"created"`
+}
+
+//easyjson:json
+type NotificationArray []Notification
+
+type NotificationList struct {
+	Unread        int32             `json:"unread"`
+	Notifications NotificationArray `json:"notifications"`
+}
+
+// NotificationsRead lists notifications to mark as read, all of them are marked when it is empty
+type NotificationsRead struct {
+	IDs []int64 `json:"ids" validate:"max=100"`
+}
+
+type UnreadCount struct {
+	Unread int32 `json:"unread"`
+}
+
+type NotificationUsecase interface {
+	GetNotifications(nickname string, unreadOnly bool, params utilities.ArrayOutParams) (*NotificationList, *utilities.Page, error)
+	MarkRead(nickname string, ids []int64) (*UnreadCount, error)
+}
+
 type JSONMessageType struct {
 	Message string `json:"message"`
 }
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+//easyjson:json
+type FieldErrorArray []FieldError
+
+type JSONValidationMessageType struct {
+	Message string          `json:"message"`
+	Errors  FieldErrorArray `json:"errors"`
+}
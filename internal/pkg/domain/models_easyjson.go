@@ -4,6 +4,7 @@ package domain
 
 import (
 	json "encoding/json"
+	strfmt "github.com/go-openapi/strfmt"
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
@@ -17,80 +18,7 @@ var (
 	_ easyjson.Marshaler
 )
 
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(in *jlexer.Lexer, out *Vote) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		if in.IsNull() {
-			in.Skip()
-			in.WantComma()
-			continue
-		}
-		switch key {
-		case "nickname":
-			out.Nickname = string(in.String())
-		case "voice":
-			out.Voice = int32(in.Int32())
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(out *jwriter.Writer, in Vote) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"nickname\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Nickname))
-	}
-	{
-		const prefix string = ",\"voice\":"
-		out.RawString(prefix)
-		out.Int32(int32(in.Voice))
-	}
-	out.RawByte('}')
-}
-
-// MarshalJSON supports json.Marshaler interface
-func (v Vote) MarshalJSON() ([]byte, error) {
-	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(&w, v)
-	return w.Buffer.BuildBytes(), w.Error
-}
-
-// MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Vote) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(w, v)
-}
-
-// UnmarshalJSON supports json.Unmarshaler interface
-func (v *Vote) UnmarshalJSON(data []byte) error {
-	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(&r, v)
-	return r.Error()
-}
-
-// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Vote) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(l, v)
-}
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(in *jlexer.Lexer, out *UserArray) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(in *jlexer.Lexer, out *WebhookDeliveryArray) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		in.Skip()
@@ -99,15 +27,15 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(in *jlexer.Lexer, ou
 		in.Delim('[')
 		if *out == nil {
 			if !in.IsDelim(']') {
-				*out = make(UserArray, 0, 1)
+				*out = make(WebhookDeliveryArray, 0, 0)
 			} else {
-				*out = UserArray{}
+				*out = WebhookDeliveryArray{}
 			}
 		} else {
 			*out = (*out)[:0]
 		}
 		for !in.IsDelim(']') {
-			var v1 User
+			var v1 WebhookDelivery
 			(v1).UnmarshalEasyJSON(in)
 			*out = append(*out, v1)
 			in.WantComma()
@@ -118,7 +46,7 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(out *jwriter.Writer, in UserArray) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(out *jwriter.Writer, in WebhookDeliveryArray) {
 	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 		out.RawString("null")
 	} else {
@@ -134,29 +62,29 @@ func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v UserArray) MarshalJSON() ([]byte, error) {
+func (v WebhookDeliveryArray) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v UserArray) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(w, v)
+func (v WebhookDeliveryArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *UserArray) UnmarshalJSON(data []byte) error {
+func (v *WebhookDeliveryArray) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *UserArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(l, v)
+func (v *WebhookDeliveryArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(in *jlexer.Lexer, out *User) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(in *jlexer.Lexer, out *WebhookDelivery) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -175,14 +103,42 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "nickname":
-			out.Nickname = string(in.String())
-		case "fullname":
-			out.Fullname = string(in.String())
-		case "about":
-			out.About = string(in.String())
-		case "email":
-			out.Email = string(in.String())
+		case "id":
+			out.ID = int64(in.Int64())
+		case "webhook":
+			out.Webhook = int64(in.Int64())
+		case "event":
+			out.Event = int64(in.Int64())
+		case "type":
+			out.Type = string(in.String())
+		case "status":
+			out.Status = string(in.String())
+		case "attempts":
+			out.Attempts = int32(in.Int32())
+		case "nextAttempt":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.NextAttempt).UnmarshalJSON(data))
+			}
+		case "lastError":
+			out.LastError = string(in.String())
+		case "responseStatus":
+			out.ResponseStatus = int32(in.Int32())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "delivered":
+			if in.IsNull() {
+				in.Skip()
+				out.Delivered = nil
+			} else {
+				if out.Delivered == nil {
+					out.Delivered = new(strfmt.DateTime)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.Delivered).UnmarshalJSON(data))
+				}
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -193,73 +149,92 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(out *jwriter.Writer, in User) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(out *jwriter.Writer, in WebhookDelivery) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Nickname != "" {
-		const prefix string = ",\"nickname\":"
-		first = false
+	{
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Nickname))
+		out.Int64(int64(in.ID))
 	}
-	if in.Fullname != "" {
-		const prefix string = ",\"fullname\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Fullname))
+	{
+		const prefix string = ",\"webhook\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Webhook))
 	}
-	if in.About != "" {
-		const prefix string = ",\"about\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.About))
+	{
+		const prefix string = ",\"event\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Event))
 	}
-	if in.Email != "" {
-		const prefix string = ",\"email\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Email))
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"attempts\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Attempts))
+	}
+	{
+		const prefix string = ",\"nextAttempt\":"
+		out.RawString(prefix)
+		out.Raw((in.NextAttempt).MarshalJSON())
+	}
+	if in.LastError != "" {
+		const prefix string = ",\"lastError\":"
+		out.RawString(prefix)
+		out.String(string(in.LastError))
+	}
+	if in.ResponseStatus != 0 {
+		const prefix string = ",\"responseStatus\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.ResponseStatus))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if in.Delivered != nil {
+		const prefix string = ",\"delivered\":"
+		out.RawString(prefix)
+		out.Raw((*in.Delivered).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v User) MarshalJSON() ([]byte, error) {
+func (v WebhookDelivery) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v User) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(w, v)
+func (v WebhookDelivery) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *User) UnmarshalJSON(data []byte) error {
+func (v *WebhookDelivery) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(l, v)
+func (v *WebhookDelivery) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain1(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(in *jlexer.Lexer, out *ThreadArray) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(in *jlexer.Lexer, out *WebhookArray) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		in.Skip()
@@ -268,15 +243,15 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(in *jlexer.Lexer, ou
 		in.Delim('[')
 		if *out == nil {
 			if !in.IsDelim(']') {
-				*out = make(ThreadArray, 0, 0)
+				*out = make(WebhookArray, 0, 0)
 			} else {
-				*out = ThreadArray{}
+				*out = WebhookArray{}
 			}
 		} else {
 			*out = (*out)[:0]
 		}
 		for !in.IsDelim(']') {
-			var v4 Thread
+			var v4 Webhook
 			(v4).UnmarshalEasyJSON(in)
 			*out = append(*out, v4)
 			in.WantComma()
@@ -287,7 +262,7 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(out *jwriter.Writer, in ThreadArray) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(out *jwriter.Writer, in WebhookArray) {
 	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 		out.RawString("null")
 	} else {
@@ -303,29 +278,29 @@ func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ThreadArray) MarshalJSON() ([]byte, error) {
+func (v WebhookArray) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ThreadArray) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(w, v)
+func (v WebhookArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ThreadArray) UnmarshalJSON(data []byte) error {
+func (v *WebhookArray) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ThreadArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(l, v)
+func (v *WebhookArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain2(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(in *jlexer.Lexer, out *Thread) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(in *jlexer.Lexer, out *Webhook) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -345,19 +320,36 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(in *jlexer.Lexer, ou
 		}
 		switch key {
 		case "id":
-			out.ID = int32(in.Int32())
-		case "title":
-			out.Title = string(in.String())
-		case "author":
-			out.Author = string(in.String())
-		case "forum":
-			out.Forum = string(in.String())
-		case "message":
-			out.Message = string(in.String())
-		case "votes":
-			out.Votes = int32(in.Int32())
-		case "slug":
-			out.Slug = string(in.String())
+			out.ID = int64(in.Int64())
+		case "url":
+			out.URL = string(in.String())
+		case "secret":
+			out.Secret = string(in.String())
+		case "events":
+			if in.IsNull() {
+				in.Skip()
+				out.Events = nil
+			} else {
+				in.Delim('[')
+				if out.Events == nil {
+					if !in.IsDelim(']') {
+						out.Events = make([]string, 0, 4)
+					} else {
+						out.Events = []string{}
+					}
+				} else {
+					out.Events = (out.Events)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 string
+					v7 = string(in.String())
+					out.Events = append(out.Events, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "active":
+			out.Active = bool(in.Bool())
 		case "created":
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Created).UnmarshalJSON(data))
@@ -372,46 +364,47 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(out *jwriter.Writer, in Thread) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(out *jwriter.Writer, in Webhook) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
 		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.Int32(int32(in.ID))
+		out.Int64(int64(in.ID))
 	}
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"url\":"
 		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.String(string(in.URL))
 	}
-	{
-		const prefix string = ",\"author\":"
+	if in.Secret != "" {
+		const prefix string = ",\"secret\":"
 		out.RawString(prefix)
-		out.String(string(in.Author))
+		out.String(string(in.Secret))
 	}
 	{
-		const prefix string = ",\"forum\":"
+		const prefix string = ",\"events\":"
 		out.RawString(prefix)
-		out.String(string(in.Forum))
+		if in.Events == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.Events {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v9))
+			}
+			out.RawByte(']')
+		}
 	}
 	{
-		const prefix string = ",\"message\":"
-		out.RawString(prefix)
-		out.String(string(in.Message))
-	}
-	if in.Votes != 0 {
-		const prefix string = ",\"votes\":"
+		const prefix string = ",\"active\":"
 		out.RawString(prefix)
-		out.Int32(int32(in.Votes))
-	}
-	if in.Slug != "" {
-		const prefix string = ",\"slug\":"
-		out.RawString(prefix)
-		out.String(string(in.Slug))
+		out.Bool(bool(in.Active))
 	}
-	if true {
+	{
 		const prefix string = ",\"created\":"
 		out.RawString(prefix)
 		out.Raw((in.Created).MarshalJSON())
@@ -420,29 +413,29 @@ func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(out *jwriter.Writer,
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Thread) MarshalJSON() ([]byte, error) {
+func (v Webhook) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(w, v)
+func (v Webhook) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Thread) UnmarshalJSON(data []byte) error {
+func (v *Webhook) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(l, v)
+func (v *Webhook) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain3(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(in *jlexer.Lexer, out *Service) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(in *jlexer.Lexer, out *VoteCast) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -461,14 +454,16 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "user":
-			out.User = int32(in.Int32())
-		case "forum":
-			out.Forum = int32(in.Int32())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "voice":
+			out.Voice = int32(in.Int32())
 		case "thread":
 			out.Thread = int32(in.Int32())
 		case "post":
 			out.Post = int64(in.Int64())
+		case "votes":
+			out.Votes = int32(in.Int32())
 		default:
 			in.SkipRecursive()
 		}
@@ -479,57 +474,128 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain5(out *jwriter.Writer, in Service) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(out *jwriter.Writer, in VoteCast) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"user\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int32(int32(in.User))
+		out.String(string(in.Nickname))
 	}
 	{
-		const prefix string = ",\"forum\":"
+		const prefix string = ",\"voice\":"
 		out.RawString(prefix)
-		out.Int32(int32(in.Forum))
+		out.Int32(int32(in.Voice))
 	}
-	{
+	if in.Thread != 0 {
 		const prefix string = ",\"thread\":"
 		out.RawString(prefix)
 		out.Int32(int32(in.Thread))
 	}
-	{
+	if in.Post != 0 {
 		const prefix string = ",\"post\":"
 		out.RawString(prefix)
 		out.Int64(int64(in.Post))
 	}
+	{
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Votes))
+	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Service) MarshalJSON() ([]byte, error) {
+func (v VoteCast) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v VoteCast) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain4(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *VoteCast) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *VoteCast) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain4(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(in *jlexer.Lexer, out *VoteArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(VoteArray, 0, 2)
+			} else {
+				*out = VoteArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v10 Vote
+			(v10).UnmarshalEasyJSON(in)
+			*out = append(*out, v10)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain5(out *jwriter.Writer, in VoteArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v11, v12 := range in {
+			if v11 > 0 {
+				out.RawByte(',')
+			}
+			(v12).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v VoteArray) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Service) MarshalEasyJSON(w *jwriter.Writer) {
+func (v VoteArray) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Service) UnmarshalJSON(data []byte) error {
+func (v *VoteArray) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Service) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *VoteArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain5(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(in *jlexer.Lexer, out *PostFull) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(in *jlexer.Lexer, out *Vote) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -548,46 +614,3048 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "post":
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "voice":
 			if in.IsNull() {
 				in.Skip()
-				out.Post = nil
+				out.Voice = nil
 			} else {
-				if out.Post == nil {
-					out.Post = new(Post)
+				if out.Voice == nil {
+					out.Voice = new(int32)
 				}
-				(*out.Post).UnmarshalEasyJSON(in)
+				*out.Voice = int32(in.Int32())
 			}
-		case "forum":
-			if in.IsNull() {
-				in.Skip()
-				out.Forum = nil
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(out *jwriter.Writer, in Vote) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"voice\":"
+		out.RawString(prefix)
+		if in.Voice == nil {
+			out.RawString("null")
+		} else {
+			out.Int32(int32(*in.Voice))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Vote) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Vote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Vote) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Vote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(in *jlexer.Lexer, out *UserArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(UserArray, 0, 0)
 			} else {
-				if out.Forum == nil {
-					out.Forum = new(Forum)
-				}
-				(*out.Forum).UnmarshalEasyJSON(in)
+				*out = UserArray{}
 			}
-		case "thread":
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v13 User
+			(v13).UnmarshalEasyJSON(in)
+			*out = append(*out, v13)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(out *jwriter.Writer, in UserArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v14, v15 := range in {
+			if v14 > 0 {
+				out.RawByte(',')
+			}
+			(v15).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UserArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UserArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UserArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UserArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(in *jlexer.Lexer, out *User) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "fullname":
+			out.Fullname = string(in.String())
+		case "about":
+			out.About = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "karma":
+			out.Karma = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(out *jwriter.Writer, in User) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Nickname != "" {
+		const prefix string = ",\"nickname\":"
+		first = false
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	if in.Fullname != "" {
+		const prefix string = ",\"fullname\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Fullname))
+	}
+	if in.About != "" {
+		const prefix string = ",\"about\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.About))
+	}
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.String(string(in.Email))
+	}
+	{
+		const prefix string = ",\"karma\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		out.Int32(int32(in.Karma))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v User) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *User) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(in *jlexer.Lexer, out *UnreadCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "unread":
+			out.Unread = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(out *jwriter.Writer, in UnreadCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.Unread))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v UnreadCount) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v UnreadCount) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *UnreadCount) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *UnreadCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(in *jlexer.Lexer, out *ThreadVotes) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "upvotes":
+			out.Upvotes = int32(in.Int32())
+		case "downvotes":
+			out.Downvotes = int32(in.Int32())
+		case "score":
+			out.Score = int32(in.Int32())
+		case "viewerVoice":
+			out.ViewerVoice = int32(in.Int32())
+		case "voters":
+			(out.Voters).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(out *jwriter.Writer, in ThreadVotes) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"upvotes\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.Upvotes))
+	}
+	{
+		const prefix string = ",\"downvotes\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Downvotes))
+	}
+	{
+		const prefix string = ",\"score\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Score))
+	}
+	if in.ViewerVoice != 0 {
+		const prefix string = ",\"viewerVoice\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.ViewerVoice))
+	}
+	{
+		const prefix string = ",\"voters\":"
+		out.RawString(prefix)
+		(in.Voters).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadVotes) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadVotes) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadVotes) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadVotes) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain11(in *jlexer.Lexer, out *ThreadMerge) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "source":
+			out.Source = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain11(out *jwriter.Writer, in ThreadMerge) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"source\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Source))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadMerge) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadMerge) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadMerge) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadMerge) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain11(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain12(in *jlexer.Lexer, out *ThreadArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(ThreadArray, 0, 0)
+			} else {
+				*out = ThreadArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v16 Thread
+			(v16).UnmarshalEasyJSON(in)
+			*out = append(*out, v16)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain12(out *jwriter.Writer, in ThreadArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v17, v18 := range in {
+			if v17 > 0 {
+				out.RawByte(',')
+			}
+			(v18).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ThreadArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ThreadArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ThreadArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ThreadArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain12(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain13(in *jlexer.Lexer, out *Thread) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int32(in.Int32())
+		case "title":
+			out.Title = string(in.String())
+		case "author":
+			out.Author = string(in.String())
+		case "forum":
+			out.Forum = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "votes":
+			out.Votes = int32(in.Int32())
+		case "slug":
+			out.Slug = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "tags":
+			if in.IsNull() {
+				in.Skip()
+				out.Tags = nil
+			} else {
+				in.Delim('[')
+				if out.Tags == nil {
+					if !in.IsDelim(']') {
+						out.Tags = make([]string, 0, 4)
+					} else {
+						out.Tags = []string{}
+					}
+				} else {
+					out.Tags = (out.Tags)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 string
+					v19 = string(in.String())
+					out.Tags = append(out.Tags, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "posts":
+			out.Posts = int32(in.Int32())
+		case "lastPostAt":
+			if in.IsNull() {
+				in.Skip()
+				out.LastPostAt = nil
+			} else {
+				if out.LastPostAt == nil {
+					out.LastPostAt = new(strfmt.DateTime)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.LastPostAt).UnmarshalJSON(data))
+				}
+			}
+		case "hot":
+			out.Hot = float64(in.Float64())
+		case "lastPostId":
+			out.LastPostID = int64(in.Int64())
+		case "lastPostAuthor":
+			out.LastPostAuthor = string(in.String())
+		case "unreadCount":
+			if in.IsNull() {
+				in.Skip()
+				out.UnreadCount = nil
+			} else {
+				if out.UnreadCount == nil {
+					out.UnreadCount = new(int32)
+				}
+				*out.UnreadCount = int32(in.Int32())
+			}
+		case "firstUnreadPostId":
+			out.FirstUnreadPostID = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain13(out *jwriter.Writer, in Thread) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.ID))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if in.Votes != 0 {
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Votes))
+	}
+	if in.Slug != "" {
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	if true {
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if len(in.Tags) != 0 {
+		const prefix string = ",\"tags\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v20, v21 := range in.Tags {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v21))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Posts != 0 {
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Posts))
+	}
+	if in.LastPostAt != nil {
+		const prefix string = ",\"lastPostAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastPostAt).MarshalJSON())
+	}
+	if in.Hot != 0 {
+		const prefix string = ",\"hot\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Hot))
+	}
+	if in.LastPostID != 0 {
+		const prefix string = ",\"lastPostId\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastPostID))
+	}
+	if in.LastPostAuthor != "" {
+		const prefix string = ",\"lastPostAuthor\":"
+		out.RawString(prefix)
+		out.String(string(in.LastPostAuthor))
+	}
+	if in.UnreadCount != nil {
+		const prefix string = ",\"unreadCount\":"
+		out.RawString(prefix)
+		out.Int32(int32(*in.UnreadCount))
+	}
+	if in.FirstUnreadPostID != 0 {
+		const prefix string = ",\"firstUnreadPostId\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.FirstUnreadPostID))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Thread) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Thread) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain13(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain14(in *jlexer.Lexer, out *TagCountArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(TagCountArray, 0, 2)
+			} else {
+				*out = TagCountArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v22 TagCount
+			(v22).UnmarshalEasyJSON(in)
+			*out = append(*out, v22)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain14(out *jwriter.Writer, in TagCountArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v23, v24 := range in {
+			if v23 > 0 {
+				out.RawByte(',')
+			}
+			(v24).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagCountArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagCountArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagCountArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagCountArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain14(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain15(in *jlexer.Lexer, out *TagCount) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "tag":
+			out.Tag = string(in.String())
+		case "count":
+			out.Count = int32(in.Int32())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain15(out *jwriter.Writer, in TagCount) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"tag\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Tag))
+	}
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Count))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v TagCount) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TagCount) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *TagCount) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TagCount) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain15(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain16(in *jlexer.Lexer, out *Subscriptions) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forums":
+			(out.Forums).UnmarshalEasyJSON(in)
+		case "threads":
+			(out.Threads).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain16(out *jwriter.Writer, in Subscriptions) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forums\":"
+		out.RawString(prefix[1:])
+		(in.Forums).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		(in.Threads).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Subscriptions) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Subscriptions) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Subscriptions) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Subscriptions) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain16(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain17(in *jlexer.Lexer, out *Subscriber) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "nickname":
+			out.Nickname = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain17(out *jwriter.Writer, in Subscriber) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Nickname))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Subscriber) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Subscriber) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Subscriber) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Subscriber) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain17(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain18(in *jlexer.Lexer, out *StreamEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain18(out *jwriter.Writer, in StreamEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	if in.Post != nil {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		(*in.Post).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		(*in.Thread).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StreamEvent) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StreamEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StreamEvent) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StreamEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain18(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain19(in *jlexer.Lexer, out *Service) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user":
+			out.User = int32(in.Int32())
+		case "forum":
+			out.Forum = int32(in.Int32())
+		case "thread":
+			out.Thread = int32(in.Int32())
+		case "post":
+			out.Post = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain19(out *jwriter.Writer, in Service) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.User))
+	}
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Forum))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Thread))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Post))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Service) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Service) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Service) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Service) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain19(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain20(in *jlexer.Lexer, out *SearchResults) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "results":
+			if in.IsNull() {
+				in.Skip()
+				out.Results = nil
+			} else {
+				in.Delim('[')
+				if out.Results == nil {
+					if !in.IsDelim(']') {
+						out.Results = make([]SearchResult, 0, 1)
+					} else {
+						out.Results = []SearchResult{}
+					}
+				} else {
+					out.Results = (out.Results)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v25 SearchResult
+					(v25).UnmarshalEasyJSON(in)
+					out.Results = append(out.Results, v25)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "next":
+			out.Next = string(in.String())
+		case "prev":
+			out.Prev = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain20(out *jwriter.Writer, in SearchResults) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"results\":"
+		out.RawString(prefix[1:])
+		if in.Results == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v26, v27 := range in.Results {
+				if v26 > 0 {
+					out.RawByte(',')
+				}
+				(v27).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		out.RawString(prefix)
+		out.String(string(in.Next))
+	}
+	if in.Prev != "" {
+		const prefix string = ",\"prev\":"
+		out.RawString(prefix)
+		out.String(string(in.Prev))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchResults) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchResults) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchResults) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchResults) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain20(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain21(in *jlexer.Lexer, out *SearchResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "rank":
+			out.Rank = float32(in.Float32())
+		case "snippet":
+			out.Snippet = string(in.String())
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain21(out *jwriter.Writer, in SearchResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"rank\":"
+		out.RawString(prefix)
+		out.Float32(float32(in.Rank))
+	}
+	{
+		const prefix string = ",\"snippet\":"
+		out.RawString(prefix)
+		out.String(string(in.Snippet))
+	}
+	if in.Post != nil {
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		(*in.Post).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		(*in.Thread).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain21(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain22(in *jlexer.Lexer, out *SearchQuery) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Query":
+			out.Query = string(in.String())
+		case "Type":
+			out.Type = string(in.String())
+		case "Lang":
+			out.Lang = string(in.String())
+		case "Forum":
+			out.Forum = string(in.String())
+		case "Thread":
+			out.Thread = int32(in.Int32())
+		case "Author":
+			out.Author = string(in.String())
+		case "Since":
+			out.Since = string(in.String())
+		case "Until":
+			out.Until = string(in.String())
+		case "Limit":
+			out.Limit = int32(in.Int32())
+		case "Cursor":
+			out.Cursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain22(out *jwriter.Writer, in SearchQuery) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Query\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Query))
+	}
+	{
+		const prefix string = ",\"Type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"Lang\":"
+		out.RawString(prefix)
+		out.String(string(in.Lang))
+	}
+	{
+		const prefix string = ",\"Forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"Thread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Thread))
+	}
+	{
+		const prefix string = ",\"Author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Until\":"
+		out.RawString(prefix)
+		out.String(string(in.Until))
+	}
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Limit))
+	}
+	{
+		const prefix string = ",\"Cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.Cursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v SearchQuery) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v SearchQuery) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *SearchQuery) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *SearchQuery) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain22(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain23(in *jlexer.Lexer, out *ReadMarker) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			out.Post = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain23(out *jwriter.Writer, in ReadMarker) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.Post))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ReadMarker) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ReadMarker) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ReadMarker) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ReadMarker) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain23(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain24(in *jlexer.Lexer, out *Quote) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "author":
+			out.Author = string(in.String())
+		case "excerpt":
+			out.Excerpt = string(in.String())
+		case "thread":
+			out.Thread = int32(in.Int32())
+		case "forum":
+			out.Forum = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain24(out *jwriter.Writer, in Quote) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	{
+		const prefix string = ",\"excerpt\":"
+		out.RawString(prefix)
+		out.String(string(in.Excerpt))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Thread))
+	}
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Quote) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Quote) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Quote) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Quote) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain24(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain25(in *jlexer.Lexer, out *PostLookupResult) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "posts":
+			(out.Posts).UnmarshalEasyJSON(in)
+		case "users":
+			(out.Users).UnmarshalEasyJSON(in)
+		case "threads":
+			(out.Threads).UnmarshalEasyJSON(in)
+		case "forums":
+			(out.Forums).UnmarshalEasyJSON(in)
+		case "missing":
+			if in.IsNull() {
+				in.Skip()
+				out.Missing = nil
+			} else {
+				in.Delim('[')
+				if out.Missing == nil {
+					if !in.IsDelim(']') {
+						out.Missing = make([]int64, 0, 8)
+					} else {
+						out.Missing = []int64{}
+					}
+				} else {
+					out.Missing = (out.Missing)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v28 int64
+					v28 = int64(in.Int64())
+					out.Missing = append(out.Missing, v28)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain25(out *jwriter.Writer, in PostLookupResult) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix[1:])
+		(in.Posts).MarshalEasyJSON(out)
+	}
+	if len(in.Users) != 0 {
+		const prefix string = ",\"users\":"
+		out.RawString(prefix)
+		(in.Users).MarshalEasyJSON(out)
+	}
+	if len(in.Threads) != 0 {
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		(in.Threads).MarshalEasyJSON(out)
+	}
+	if len(in.Forums) != 0 {
+		const prefix string = ",\"forums\":"
+		out.RawString(prefix)
+		(in.Forums).MarshalEasyJSON(out)
+	}
+	if len(in.Missing) != 0 {
+		const prefix string = ",\"missing\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v29, v30 := range in.Missing {
+				if v29 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v30))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostLookupResult) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostLookupResult) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostLookupResult) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostLookupResult) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain25(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain26(in *jlexer.Lexer, out *PostLookup) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ids":
+			if in.IsNull() {
+				in.Skip()
+				out.IDs = nil
+			} else {
+				in.Delim('[')
+				if out.IDs == nil {
+					if !in.IsDelim(']') {
+						out.IDs = make([]int64, 0, 8)
+					} else {
+						out.IDs = []int64{}
+					}
+				} else {
+					out.IDs = (out.IDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v31 int64
+					v31 = int64(in.Int64())
+					out.IDs = append(out.IDs, v31)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "related":
+			if in.IsNull() {
+				in.Skip()
+				out.Related = nil
+			} else {
+				in.Delim('[')
+				if out.Related == nil {
+					if !in.IsDelim(']') {
+						out.Related = make([]string, 0, 4)
+					} else {
+						out.Related = []string{}
+					}
+				} else {
+					out.Related = (out.Related)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v32 string
+					v32 = string(in.String())
+					out.Related = append(out.Related, v32)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain26(out *jwriter.Writer, in PostLookup) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ids\":"
+		out.RawString(prefix[1:])
+		if in.IDs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v33, v34 := range in.IDs {
+				if v33 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v34))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"related\":"
+		out.RawString(prefix)
+		if in.Related == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v35, v36 := range in.Related {
+				if v35 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v36))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostLookup) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostLookup) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostLookup) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostLookup) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain26(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain27(in *jlexer.Lexer, out *PostFull) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "forum":
+			if in.IsNull() {
+				in.Skip()
+				out.Forum = nil
+			} else {
+				if out.Forum == nil {
+					out.Forum = new(Forum)
+				}
+				(*out.Forum).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
+		case "author":
+			if in.IsNull() {
+				in.Skip()
+				out.User = nil
+			} else {
+				if out.User == nil {
+					out.User = new(User)
+				}
+				(*out.User).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain27(out *jwriter.Writer, in PostFull) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix[1:])
+		if in.Post == nil {
+			out.RawString("null")
+		} else {
+			(*in.Post).MarshalEasyJSON(out)
+		}
+	}
+	if in.Forum != nil {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		(*in.Forum).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		(*in.Thread).MarshalEasyJSON(out)
+	}
+	if in.User != nil {
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		(*in.User).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostFull) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain27(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostFull) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain27(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostFull) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain27(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain27(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain28(in *jlexer.Lexer, out *PostArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(PostArray, 0, 0)
+			} else {
+				*out = PostArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v37 Post
+			(v37).UnmarshalEasyJSON(in)
+			*out = append(*out, v37)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain28(out *jwriter.Writer, in PostArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v38, v39 := range in {
+			if v38 > 0 {
+				out.RawByte(',')
+			}
+			(v39).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain28(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain28(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain28(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain28(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain29(in *jlexer.Lexer, out *Post) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "parent":
+			out.Parent = int64(in.Int64())
+		case "author":
+			out.Author = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		case "isEdited":
+			out.IsEdited = bool(in.Bool())
+		case "forum":
+			out.Forum = string(in.String())
+		case "thread":
+			out.Thread = int32(in.Int32())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "votes":
+			out.Votes = int32(in.Int32())
+		case "mentions":
+			if in.IsNull() {
+				in.Skip()
+				out.Mentions = nil
+			} else {
+				in.Delim('[')
+				if out.Mentions == nil {
+					if !in.IsDelim(']') {
+						out.Mentions = make([]string, 0, 4)
+					} else {
+						out.Mentions = []string{}
+					}
+				} else {
+					out.Mentions = (out.Mentions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v40 string
+					v40 = string(in.String())
+					out.Mentions = append(out.Mentions, v40)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "quotes":
+			if in.IsNull() {
+				in.Skip()
+				out.Quotes = nil
+			} else {
+				in.Delim('[')
+				if out.Quotes == nil {
+					if !in.IsDelim(']') {
+						out.Quotes = make([]int64, 0, 8)
+					} else {
+						out.Quotes = []int64{}
+					}
+				} else {
+					out.Quotes = (out.Quotes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v41 int64
+					v41 = int64(in.Int64())
+					out.Quotes = append(out.Quotes, v41)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "quoted":
+			if in.IsNull() {
+				in.Skip()
+				out.Quoted = nil
+			} else {
+				in.Delim('[')
+				if out.Quoted == nil {
+					if !in.IsDelim(']') {
+						out.Quoted = make([]Quote, 0, 1)
+					} else {
+						out.Quoted = []Quote{}
+					}
+				} else {
+					out.Quoted = (out.Quoted)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v42 Quote
+					(v42).UnmarshalEasyJSON(in)
+					out.Quoted = append(out.Quoted, v42)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "messageHtml":
+			out.MessageHTML = string(in.String())
+		case "repliesCount":
+			out.RepliesCount = int32(in.Int32())
+		case "hasMore":
+			out.HasMore = bool(in.Bool())
+		case "children":
+			(out.Children).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain29(out *jwriter.Writer, in Post) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	if in.Parent != 0 {
+		const prefix string = ",\"parent\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Parent))
+	}
+	if in.Author != "" {
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	if in.Message != "" {
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if in.IsEdited {
+		const prefix string = ",\"isEdited\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsEdited))
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	if in.Thread != 0 {
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Thread))
+	}
+	if true {
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if in.Votes != 0 {
+		const prefix string = ",\"votes\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Votes))
+	}
+	if len(in.Mentions) != 0 {
+		const prefix string = ",\"mentions\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v43, v44 := range in.Mentions {
+				if v43 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v44))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Quotes) != 0 {
+		const prefix string = ",\"quotes\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v45, v46 := range in.Quotes {
+				if v45 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v46))
+			}
+			out.RawByte(']')
+		}
+	}
+	if len(in.Quoted) != 0 {
+		const prefix string = ",\"quoted\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v47, v48 := range in.Quoted {
+				if v47 > 0 {
+					out.RawByte(',')
+				}
+				(v48).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.MessageHTML != "" {
+		const prefix string = ",\"messageHtml\":"
+		out.RawString(prefix)
+		out.String(string(in.MessageHTML))
+	}
+	if in.RepliesCount != 0 {
+		const prefix string = ",\"repliesCount\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.RepliesCount))
+	}
+	if in.HasMore {
+		const prefix string = ",\"hasMore\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasMore))
+	}
+	if len(in.Children) != 0 {
+		const prefix string = ",\"children\":"
+		out.RawString(prefix)
+		(in.Children).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Post) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain29(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain29(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Post) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain29(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain29(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain30(in *jlexer.Lexer, out *NotificationsRead) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "ids":
+			if in.IsNull() {
+				in.Skip()
+				out.IDs = nil
+			} else {
+				in.Delim('[')
+				if out.IDs == nil {
+					if !in.IsDelim(']') {
+						out.IDs = make([]int64, 0, 8)
+					} else {
+						out.IDs = []int64{}
+					}
+				} else {
+					out.IDs = (out.IDs)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v49 int64
+					v49 = int64(in.Int64())
+					out.IDs = append(out.IDs, v49)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain30(out *jwriter.Writer, in NotificationsRead) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"ids\":"
+		out.RawString(prefix[1:])
+		if in.IDs == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v50, v51 := range in.IDs {
+				if v50 > 0 {
+					out.RawByte(',')
+				}
+				out.Int64(int64(v51))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationsRead) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain30(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationsRead) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain30(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationsRead) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain30(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationsRead) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain30(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain31(in *jlexer.Lexer, out *NotificationList) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "unread":
+			out.Unread = int32(in.Int32())
+		case "notifications":
+			(out.Notifications).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain31(out *jwriter.Writer, in NotificationList) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"unread\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.Unread))
+	}
+	{
+		const prefix string = ",\"notifications\":"
+		out.RawString(prefix)
+		(in.Notifications).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationList) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain31(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationList) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain31(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationList) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain31(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationList) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain31(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain32(in *jlexer.Lexer, out *NotificationArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(NotificationArray, 0, 1)
+			} else {
+				*out = NotificationArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v52 Notification
+			(v52).UnmarshalEasyJSON(in)
+			*out = append(*out, v52)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain32(out *jwriter.Writer, in NotificationArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v53, v54 := range in {
+			if v53 > 0 {
+				out.RawByte(',')
+			}
+			(v54).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v NotificationArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain32(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v NotificationArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain32(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *NotificationArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain32(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *NotificationArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain32(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain33(in *jlexer.Lexer, out *Notification) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int64(in.Int64())
+		case "type":
+			out.Type = string(in.String())
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
+		case "read":
+			out.Read = bool(in.Bool())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain33(out *jwriter.Writer, in Notification) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		if in.Post == nil {
+			out.RawString("null")
+		} else {
+			(*in.Post).MarshalEasyJSON(out)
+		}
+	}
+	{
+		const prefix string = ",\"read\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Read))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Notification) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain33(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Notification) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain33(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Notification) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain33(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Notification) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain33(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain34(in *jlexer.Lexer, out *JSONValidationMessageType) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "message":
+			out.Message = string(in.String())
+		case "errors":
+			(out.Errors).UnmarshalEasyJSON(in)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain34(out *jwriter.Writer, in JSONValidationMessageType) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		(in.Errors).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JSONValidationMessageType) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain34(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JSONValidationMessageType) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain34(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JSONValidationMessageType) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain34(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JSONValidationMessageType) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain34(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain35(in *jlexer.Lexer, out *JSONMessageType) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain35(out *jwriter.Writer, in JSONMessageType) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v JSONMessageType) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain35(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v JSONMessageType) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain35(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *JSONMessageType) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain35(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *JSONMessageType) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain35(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain36(in *jlexer.Lexer, out *ForumArray) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(ForumArray, 0, 0)
+			} else {
+				*out = ForumArray{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v55 Forum
+			(v55).UnmarshalEasyJSON(in)
+			*out = append(*out, v55)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain36(out *jwriter.Writer, in ForumArray) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v56, v57 := range in {
+			if v56 > 0 {
+				out.RawByte(',')
+			}
+			(v57).MarshalEasyJSON(out)
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumArray) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain36(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain36(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumArray) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain36(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain36(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain37(in *jlexer.Lexer, out *Forum) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "slug":
+			out.Slug = string(in.String())
+		case "posts":
+			out.Posts = int64(in.Int64())
+		case "threads":
+			out.Threads = int64(in.Int64())
+		case "lastPostAt":
 			if in.IsNull() {
 				in.Skip()
-				out.Thread = nil
+				out.LastPostAt = nil
 			} else {
-				if out.Thread == nil {
-					out.Thread = new(Thread)
+				if out.LastPostAt == nil {
+					out.LastPostAt = new(strfmt.DateTime)
 				}
-				(*out.Thread).UnmarshalEasyJSON(in)
-			}
-		case "author":
-			if in.IsNull() {
-				in.Skip()
-				out.User = nil
-			} else {
-				if out.User == nil {
-					out.User = new(User)
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.LastPostAt).UnmarshalJSON(data))
 				}
-				(*out.User).UnmarshalEasyJSON(in)
 			}
+		case "lastPostId":
+			out.LastPostID = int64(in.Int64())
+		case "lastPostAuthor":
+			out.LastPostAuthor = string(in.String())
+		case "lastPostThread":
+			out.LastPostThread = int32(in.Int32())
 		default:
 			in.SkipRecursive()
 		}
@@ -598,61 +3666,82 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(out *jwriter.Writer, in PostFull) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain37(out *jwriter.Writer, in Forum) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"post\":"
+		const prefix string = ",\"title\":"
 		out.RawString(prefix[1:])
-		if in.Post == nil {
-			out.RawString("null")
-		} else {
-			(*in.Post).MarshalEasyJSON(out)
-		}
+		out.String(string(in.Title))
 	}
-	if in.Forum != nil {
-		const prefix string = ",\"forum\":"
+	{
+		const prefix string = ",\"user\":"
 		out.RawString(prefix)
-		(*in.Forum).MarshalEasyJSON(out)
+		out.String(string(in.User))
 	}
-	if in.Thread != nil {
-		const prefix string = ",\"thread\":"
+	{
+		const prefix string = ",\"slug\":"
 		out.RawString(prefix)
-		(*in.Thread).MarshalEasyJSON(out)
+		out.String(string(in.Slug))
 	}
-	if in.User != nil {
-		const prefix string = ",\"author\":"
+	if in.Posts != 0 {
+		const prefix string = ",\"posts\":"
 		out.RawString(prefix)
-		(*in.User).MarshalEasyJSON(out)
+		out.Int64(int64(in.Posts))
+	}
+	if in.Threads != 0 {
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Threads))
+	}
+	if in.LastPostAt != nil {
+		const prefix string = ",\"lastPostAt\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastPostAt).MarshalJSON())
+	}
+	if in.LastPostID != 0 {
+		const prefix string = ",\"lastPostId\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.LastPostID))
+	}
+	if in.LastPostAuthor != "" {
+		const prefix string = ",\"lastPostAuthor\":"
+		out.RawString(prefix)
+		out.String(string(in.LastPostAuthor))
+	}
+	if in.LastPostThread != 0 {
+		const prefix string = ",\"lastPostThread\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.LastPostThread))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostFull) MarshalJSON() ([]byte, error) {
+func (v Forum) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain37(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostFull) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain6(w, v)
+func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain37(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostFull) UnmarshalJSON(data []byte) error {
+func (v *Forum) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain37(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostFull) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain6(l, v)
+func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain37(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(in *jlexer.Lexer, out *PostArray) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain38(in *jlexer.Lexer, out *FieldErrorArray) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		in.Skip()
@@ -661,17 +3750,17 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(in *jlexer.Lexer, ou
 		in.Delim('[')
 		if *out == nil {
 			if !in.IsDelim(']') {
-				*out = make(PostArray, 0, 0)
+				*out = make(FieldErrorArray, 0, 2)
 			} else {
-				*out = PostArray{}
+				*out = FieldErrorArray{}
 			}
 		} else {
 			*out = (*out)[:0]
 		}
 		for !in.IsDelim(']') {
-			var v7 Post
-			(v7).UnmarshalEasyJSON(in)
-			*out = append(*out, v7)
+			var v58 FieldError
+			(v58).UnmarshalEasyJSON(in)
+			*out = append(*out, v58)
 			in.WantComma()
 		}
 		in.Delim(']')
@@ -680,45 +3769,45 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(out *jwriter.Writer, in PostArray) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain38(out *jwriter.Writer, in FieldErrorArray) {
 	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 		out.RawString("null")
 	} else {
 		out.RawByte('[')
-		for v8, v9 := range in {
-			if v8 > 0 {
+		for v59, v60 := range in {
+			if v59 > 0 {
 				out.RawByte(',')
 			}
-			(v9).MarshalEasyJSON(out)
+			(v60).MarshalEasyJSON(out)
 		}
 		out.RawByte(']')
 	}
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostArray) MarshalJSON() ([]byte, error) {
+func (v FieldErrorArray) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain38(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostArray) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain7(w, v)
+func (v FieldErrorArray) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain38(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostArray) UnmarshalJSON(data []byte) error {
+func (v *FieldErrorArray) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain38(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain7(l, v)
+func (v *FieldErrorArray) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain38(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(in *jlexer.Lexer, out *Post) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain39(in *jlexer.Lexer, out *FieldError) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -737,24 +3826,10 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int64(in.Int64())
-		case "parent":
-			out.Parent = int64(in.Int64())
-		case "author":
-			out.Author = string(in.String())
+		case "field":
+			out.Field = string(in.String())
 		case "message":
 			out.Message = string(in.String())
-		case "isEdited":
-			out.IsEdited = bool(in.Bool())
-		case "forum":
-			out.Forum = string(in.String())
-		case "thread":
-			out.Thread = int32(in.Int32())
-		case "created":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Created).UnmarshalJSON(data))
-			}
 		default:
 			in.SkipRecursive()
 		}
@@ -765,77 +3840,113 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(out *jwriter.Writer, in Post) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain39(out *jwriter.Writer, in FieldError) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"field\":"
 		out.RawString(prefix[1:])
-		out.Int64(int64(in.ID))
-	}
-	if in.Parent != 0 {
-		const prefix string = ",\"parent\":"
-		out.RawString(prefix)
-		out.Int64(int64(in.Parent))
-	}
-	if in.Author != "" {
-		const prefix string = ",\"author\":"
-		out.RawString(prefix)
-		out.String(string(in.Author))
+		out.String(string(in.Field))
 	}
-	if in.Message != "" {
+	{
 		const prefix string = ",\"message\":"
 		out.RawString(prefix)
 		out.String(string(in.Message))
 	}
-	if in.IsEdited {
-		const prefix string = ",\"isEdited\":"
-		out.RawString(prefix)
-		out.Bool(bool(in.IsEdited))
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain39(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain39(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain39(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain39(l, v)
+}
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain40(in *jlexer.Lexer, out *FeedVisit) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
 	}
-	if in.Forum != "" {
-		const prefix string = ",\"forum\":"
-		out.RawString(prefix)
-		out.String(string(in.Forum))
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "lastPost":
+			out.LastPost = int64(in.Int64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
-	if in.Thread != 0 {
-		const prefix string = ",\"thread\":"
-		out.RawString(prefix)
-		out.Int32(int32(in.Thread))
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
 	}
-	if true {
-		const prefix string = ",\"created\":"
-		out.RawString(prefix)
-		out.Raw((in.Created).MarshalJSON())
+}
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain40(out *jwriter.Writer, in FeedVisit) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"lastPost\":"
+		out.RawString(prefix[1:])
+		out.Int64(int64(in.LastPost))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Post) MarshalJSON() ([]byte, error) {
+func (v FeedVisit) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain40(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain8(w, v)
+func (v FeedVisit) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain40(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Post) UnmarshalJSON(data []byte) error {
+func (v *FeedVisit) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain40(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain8(l, v)
+func (v *FeedVisit) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain40(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(in *jlexer.Lexer, out *JSONMessageType) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain41(in *jlexer.Lexer, out *Feed) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -854,8 +3965,10 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(in *jlexer.Lexer, ou
 			continue
 		}
 		switch key {
-		case "message":
-			out.Message = string(in.String())
+		case "posts":
+			(out.Posts).UnmarshalEasyJSON(in)
+		case "hasMore":
+			out.HasMore = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -866,42 +3979,47 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(in *jlexer.Lexer, ou
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(out *jwriter.Writer, in JSONMessageType) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain41(out *jwriter.Writer, in Feed) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"posts\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Message))
+		(in.Posts).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"hasMore\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasMore))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v JSONMessageType) MarshalJSON() ([]byte, error) {
+func (v Feed) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain41(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v JSONMessageType) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain9(w, v)
+func (v Feed) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain41(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *JSONMessageType) UnmarshalJSON(data []byte) error {
+func (v *Feed) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain41(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *JSONMessageType) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain9(l, v)
+func (v *Feed) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain41(l, v)
 }
-func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(in *jlexer.Lexer, out *Forum) {
+func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain42(in *jlexer.Lexer, out *CacheStats) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -920,16 +4038,20 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(in *jlexer.Lexer, o
 			continue
 		}
 		switch key {
-		case "title":
-			out.Title = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "slug":
-			out.Slug = string(in.String())
-		case "posts":
-			out.Posts = int64(in.Int64())
-		case "threads":
-			out.Threads = int64(in.Int64())
+		case "backend":
+			out.Backend = string(in.String())
+		case "hits":
+			out.Hits = int64(in.Int64())
+		case "misses":
+			out.Misses = int64(in.Int64())
+		case "hitRatio":
+			out.HitRatio = float64(in.Float64())
+		case "invalidations":
+			out.Invalidations = int64(in.Int64())
+		case "errors":
+			out.Errors = int64(in.Int64())
+		case "entries":
+			out.Entries = int64(in.Int64())
 		default:
 			in.SkipRecursive()
 		}
@@ -940,58 +4062,68 @@ func easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(in *jlexer.Lexer, o
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(out *jwriter.Writer, in Forum) {
+func easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain42(out *jwriter.Writer, in CacheStats) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"backend\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Title))
+		out.String(string(in.Backend))
 	}
 	{
-		const prefix string = ",\"user\":"
+		const prefix string = ",\"hits\":"
 		out.RawString(prefix)
-		out.String(string(in.User))
+		out.Int64(int64(in.Hits))
 	}
 	{
-		const prefix string = ",\"slug\":"
+		const prefix string = ",\"misses\":"
 		out.RawString(prefix)
-		out.String(string(in.Slug))
+		out.Int64(int64(in.Misses))
 	}
-	if in.Posts != 0 {
-		const prefix string = ",\"posts\":"
+	{
+		const prefix string = ",\"hitRatio\":"
 		out.RawString(prefix)
-		out.Int64(int64(in.Posts))
+		out.Float64(float64(in.HitRatio))
 	}
-	if in.Threads != 0 {
-		const prefix string = ",\"threads\":"
+	{
+		const prefix string = ",\"invalidations\":"
 		out.RawString(prefix)
-		out.Int64(int64(in.Threads))
+		out.Int64(int64(in.Invalidations))
+	}
+	{
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Errors))
+	}
+	if in.Entries != 0 {
+		const prefix string = ",\"entries\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.Entries))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Forum) MarshalJSON() ([]byte, error) {
+func (v CacheStats) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(&w, v)
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain42(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain10(w, v)
+func (v CacheStats) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeTechnoparkDbmsInternalPkgDomain42(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Forum) UnmarshalJSON(data []byte) error {
+func (v *CacheStats) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(&r, v)
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain42(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain10(l, v)
+func (v *CacheStats) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeTechnoparkDbmsInternalPkgDomain42(l, v)
 }
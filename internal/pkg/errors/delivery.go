@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/utilities"
 )
 
 func JSONMessage(m string) domain.JSONMessageType {
@@ -15,6 +16,10 @@ func JSONErrorMessage(err error) domain.JSONMessageType {
 	return domain.JSONMessageType{Message: fmt.Sprint(err)}
 }
 
+func JSONValidationMessage(fieldErrors domain.FieldErrorArray) domain.JSONValidationMessageType {
+	return domain.JSONValidationMessageType{Message: "validation error", Errors: fieldErrors}
+}
+
 var (
 	JSONEncodeErrorMessage      = JSONMessage("json encode")
 	JSONDecodeErrorMessage      = JSONMessage("json decode")
@@ -32,7 +37,7 @@ var (
 
 func CodeFromDeliveryError(deliveryError error) int {
 	switch deliveryError {
-	case URLParamsError, QuerystringParseError, JSONUnmarshallError:
+	case URLParamsError, QuerystringParseError, JSONUnmarshallError, utilities.CursorError:
 		return http.StatusBadRequest
 	case JSONEncodeError:
 		return http.StatusInternalServerError
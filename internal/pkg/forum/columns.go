@@ -0,0 +1,31 @@
+package forum
+
+import (
+	"github.com/go-openapi/strfmt"
+	"technopark-dbms/internal/pkg/domain"
+	"time"
+)
+
+// Columns are the forum columns Scan expects
+const Columns = "title, username, slug, posts, threads, last_post_at, last_post_id, last_post_author, last_post_thread"
+
+type row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan reads Columns into the forum, a forum has no last post until the first post is created
+func Scan(r row, f *domain.Forum) error {
+	var lastPostAt *time.Time
+	var lastPostID *int64
+	var lastPostAuthor *string
+	var lastPostThread *int32
+	err := r.Scan(&f.Title, &f.User, &f.Slug, &f.Posts, &f.Threads, &lastPostAt, &lastPostID, &lastPostAuthor, &lastPostThread)
+	if err != nil {
+		return err
+	}
+	if lastPostID != nil {
+		lastPost := strfmt.DateTime(*lastPostAt)
+		f.LastPostAt, f.LastPostID, f.LastPostAuthor, f.LastPostThread = &lastPost, *lastPostID, *lastPostAuthor, *lastPostThread
+	}
+	return nil
+}
@@ -10,7 +10,10 @@ import (
 	"technopark-dbms/internal/pkg/domain"
 	"technopark-dbms/internal/pkg/errors"
 	"technopark-dbms/internal/pkg/forum"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
 )
 
 type forumHandler struct {
@@ -29,6 +32,8 @@ func NewForumHandler(r *router.Router, fu domain.ForumUsecase) {
 	s.POST("/{slug}/create", h.forumCreateThreadHandler)
 	s.GET("/{slug}/users", h.forumGetUsersHandler)
 	s.GET("/{slug}/threads", h.forumGetThreadsHandler)
+	s.GET("/{slug}/tags", h.forumGetTagsHandler)
+	s.POST("/{slug}/read", h.forumMarkReadHandler)
 }
 
 // Create
@@ -47,6 +52,11 @@ func (handler *forumHandler) forumCreateHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedForum, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
 	createdForum, err := handler.forumUsecase.CreateForum(*parsedForum)
 	responseStatus := fasthttp.StatusCreated
 	if err != nil {
@@ -96,6 +106,11 @@ func (handler *forumHandler) forumCreateThreadHandler(ctx *fasthttp.RequestCtx)
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedThread, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
 	createdThread, err := handler.forumUsecase.CreateThread(slugValue, *parsedThread)
 	respStatus := fasthttp.StatusCreated
 	if err != nil {
@@ -116,39 +131,40 @@ func (handler *forumHandler) forumCreateThreadHandler(ctx *fasthttp.RequestCtx)
 
 func (handler *forumHandler) forumGetUsersHandler(ctx *fasthttp.RequestCtx) {
 	slugValue := ctx.UserValue("slug").(string)
-	params, err := utilities.NewArrayOutParams(ctx.QueryArgs())
-	if err != nil {
-		log.WithError(err).Error(errors.QuerystringParseError)
-		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceAny)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
 		return
 	}
 
-	foundUsers, err := handler.forumUsecase.GetUsers(slugValue, *params)
+	foundUsers, page, err := handler.forumUsecase.GetUsers(slugValue, *params)
 	if err != nil {
 		log.WithError(err).Error("forum get users error")
 		if err == forum.NotFound {
 			utilities.Resp(ctx, http.StatusNotFound, errors.JSONErrorMessage(err))
 			return
 		}
-		utilities.Resp(ctx, http.StatusInternalServerError, errors.JSONErrorMessage(err))
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(err), errors.JSONErrorMessage(err))
 		return
 	}
 
+	utilities.SetPageLinks(ctx, page)
 	utilities.Resp(ctx, http.StatusOK, foundUsers)
 }
 
 func (handler *forumHandler) forumGetThreadsHandler(ctx *fasthttp.RequestCtx) {
 	slugValue := ctx.UserValue("slug").(string)
-	params, err := utilities.NewArrayOutParams(ctx.URI().QueryArgs())
-	if err != nil {
-		log.WithError(err).Error(errors.QuerystringParseError)
-		utilities.Resp(ctx,
-			fasthttp.StatusInternalServerError,
-			errors.JSONErrorMessage(err))
+	params, fieldErrors := validation.ListParams(ctx.URI().QueryArgs(), validation.SinceDate, thread.Sorts...)
+	fieldErrors = append(fieldErrors, validation.Var("window", string(ctx.URI().QueryArgs().Peek("window")), "oneof=day week month year all")...)
+	viewer := utilities.Viewer(ctx)
+	utilities.VaryOnViewer(ctx)
+	fieldErrors = append(fieldErrors, validation.Var(utilities.ViewerHeader, viewer, "nickname")...)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
 		return
 	}
 
-	foundUsers, err := handler.forumUsecase.GetThreads(slugValue, *params)
+	foundUsers, page, err := handler.forumUsecase.GetThreads(slugValue, viewer, *params)
 	if err != nil {
 		log.WithError(err).Error("forum get users error")
 		if err == forum.NotFound {
@@ -158,8 +174,55 @@ func (handler *forumHandler) forumGetThreadsHandler(ctx *fasthttp.RequestCtx) {
 			return
 		}
 		utilities.Resp(ctx,
-			fasthttp.StatusInternalServerError,
+			errors.CodeFromDeliveryError(err),
 			errors.JSONErrorMessage(err))
+		return
 	}
+	utilities.SetPageLinks(ctx, page)
 	utilities.Resp(ctx, fasthttp.StatusOK, foundUsers)
 }
+
+func (handler *forumHandler) forumGetTagsHandler(ctx *fasthttp.RequestCtx) {
+	slugValue := ctx.UserValue("slug").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceAny)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundTags, page, err := handler.forumUsecase.GetTags(slugValue, *params)
+	if err != nil {
+		log.WithError(err).Error("forum get tags error")
+		if err == forum.NotFound {
+			utilities.Resp(ctx,
+				fasthttp.StatusNotFound,
+				errors.JSONMessage(fmt.Sprintf("Can't find forum with slug: %s", slugValue)))
+			return
+		}
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundTags)
+}
+
+func (handler *forumHandler) forumMarkReadHandler(ctx *fasthttp.RequestCtx) {
+	slugValue := ctx.UserValue("slug").(string)
+	viewer := utilities.Viewer(ctx)
+	if fieldErrors := validation.Var(utilities.ViewerHeader, viewer, "required,nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundForum, err := handler.forumUsecase.MarkForumRead(slugValue, viewer)
+	if err != nil {
+		log.WithError(err).Error("forum mark read error")
+		if err == forum.NotFound || err == user.NotExistsError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, foundForum)
+}
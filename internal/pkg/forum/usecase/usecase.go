@@ -3,17 +3,21 @@ package usecase
 import (
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx"
-	log "github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+	"technopark-dbms/internal/pkg/cache"
 	"technopark-dbms/internal/pkg/domain"
 	"technopark-dbms/internal/pkg/forum"
 	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/webhook"
 )
 
 const (
 	createForumQuery     = "insert into forums(title, username, slug) values ($1, (select nickname from users u where u.nickname = $2), $3) returning title, username, slug, posts, threads;"
 	forumExistsQuery     = "select slug from forums where slug = $1;"
-	getForumDetailsQuery = "select title, username, slug, posts, threads from forums where slug = $1;"
+	getForumDetailsQuery = "select " + forum.Columns + " from forums where slug = $1;"
 	createFTQuery        = "insert into f_t(f_slug, t_id) values ($1, $2);"
 )
 
@@ -23,6 +27,7 @@ type forumUsecase struct {
 	DB     *pgx.ConnPool
 	UUCase domain.UserUsecase
 	TUCase domain.ThreadUsecase
+	Cache  *cache.Cache
 }
 
 func (u *forumUsecase) ForumExists(slug string) (bool, error) {
@@ -37,11 +42,12 @@ func (u *forumUsecase) ForumExists(slug string) (bool, error) {
 	return true, nil
 }
 
-func NewForumUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase, threadUsecase domain.ThreadUsecase) domain.ForumUsecase {
+func NewForumUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase, threadUsecase domain.ThreadUsecase, c *cache.Cache) domain.ForumUsecase {
 	return &forumUsecase{
 		DB:     db,
 		UUCase: userUsecase,
 		TUCase: threadUsecase,
+		Cache:  c,
 	}
 }
 
@@ -60,30 +66,44 @@ func (u *forumUsecase) CreateForum(f domain.Forum) (*domain.Forum, error) {
 		return nil, err
 	}
 
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	createdForum := &domain.Forum{}
-	err = u.DB.QueryRow(createForumQuery, f.Title, f.User, f.Slug).
+	err = tx.QueryRow(createForumQuery, f.Title, f.User, f.Slug).
 		Scan(&createdForum.Title, &createdForum.User, &createdForum.Slug, &createdForum.Posts, &createdForum.Threads)
 	if err != nil {
 		return nil, err
 	}
-	return createdForum, err
+	if err = webhook.Enqueue(tx, webhook.ForumCreated, createdForum); err != nil {
+		return nil, err
+	}
+	return createdForum, tx.Commit()
 }
 
+// GetForumDetails is cached until a thread or a post is created in the forum
 func (u *forumUsecase) GetForumDetails(slug string) (*domain.Forum, error) {
 	f := &domain.Forum{}
-	err := u.DB.QueryRow(getForumDetailsQuery, slug).Scan(&f.Title, &f.User, &f.Slug, &f.Posts, &f.Threads)
+	if u.Cache.Load(cache.ForumKey(slug), f) {
+		return f, nil
+	}
+	err := forum.Scan(u.DB.QueryRow(getForumDetailsQuery, slug), f)
 	if err == pgx.ErrNoRows {
 		return nil, forum.NotFound
 	} else if err != nil {
 		return nil, err
 	}
+	u.Cache.Store(cache.ForumKey(slug), f)
 	return f, nil
 }
 
 func generateCreateThreadQuery(forumSlug string, t domain.Thread) (string, []interface{}, error) {
 	values := make([]interface{}, 0)
 	values = append(values, t.Author, forumSlug, t.Message, t.Title)
-	req := "insert into threads(author, forum, message, title, created, slug) values ($1, $2, $3, $4, $5, $6)"
+	req := "insert into threads(author, forum, message, title, created, slug, tags) values ($1, $2, $3, $4, $5, $6, $7)"
 	if t.Created.String() != "" {
 		values = append(values, t.Created)
 	} else {
@@ -94,7 +114,12 @@ func generateCreateThreadQuery(forumSlug string, t domain.Thread) (string, []int
 	} else {
 		values = append(values, nil)
 	}
-	req += "returning id, author, (select f.slug from forums f where f.slug = $2), message, title, created, slug;"
+	tags := thread.NormalizeTags(t.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	values = append(values, tags)
+	req += "returning id, author, (select f.slug from forums f where f.slug = $2), message, title, created, slug, tags;"
 	return req, values, nil
 }
 
@@ -126,130 +151,233 @@ func (u *forumUsecase) CreateThread(forumSlug string, t domain.Thread) (*domain.
 	if err != nil {
 		return nil, err
 	}
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	newThread := &domain.Thread{}
 	var slug *string
-	err = u.DB.QueryRow(createThreadQuery, args...).
-		Scan(&newThread.ID, &newThread.Author, &newThread.Forum, &newThread.Message, &newThread.Title, &newThread.Created, &slug)
+	err = tx.QueryRow(createThreadQuery, args...).
+		Scan(&newThread.ID, &newThread.Author, &newThread.Forum, &newThread.Message, &newThread.Title, &newThread.Created, &slug, &newThread.Tags)
 	if err != nil {
 		return nil, err
 	}
 	if slug != nil {
 		newThread.Slug = *slug
 	}
+	if err = webhook.Enqueue(tx, webhook.ThreadCreated, newThread); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	u.Cache.Invalidate(cache.ForumKey(forumSlug))
 	return newThread, nil
 }
 
-func generateUserRequest(slug string, params utilities.ArrayOutParams) (string, []interface{}, error) {
+const (
+	usersCursorScope   = "forum_users"
+	threadsCursorScope = "forum_threads"
+	tagsCursorScope    = "forum_tags"
+)
+
+func generateUserRequest(slug string, params utilities.ArrayOutParams, cursor *utilities.Cursor) (string, []interface{}, error) {
+	desc, since := params.Desc, params.Since
+	if cursor != nil {
+		desc, since = cursor.Desc != cursor.Backward, cursor.Key[0]
+	}
 	var order string
 	var s string
-	if params.Desc {
+	if desc {
 		order, s = "desc", " < "
 	} else {
 		order, s = "asc", " > "
 	}
 	var query string
 	args := make([]interface{}, 0)
-	if params.Since != "" {
-		query = "select u, fullname, about, email from f_u join users on f_u.u = users.nickname where f = $1 and u " + s + " $2 order by nickname " + order + " limit $3;"
-		args = append(args, slug, params.Since, params.Limit)
+	if since != "" {
+		query = "select " + user.Columns + " from f_u join users on f_u.u = users.nickname where f = $1 and u " + s + " $2 order by nickname " + order + " limit $3;"
+		args = append(args, slug, since, params.Limit)
 	} else {
-		query = "select u, fullname, about, email from f_u join users on f_u.u = users.nickname where f = $1 order by nickname " + order + " limit $2;"
+		query = "select " + user.Columns + " from f_u join users on f_u.u = users.nickname where f = $1 order by nickname " + order + " limit $2;"
 		args = append(args, slug, params.Limit)
 	}
 
 	return query, args, nil
 }
 
-func (u *forumUsecase) GetUsers(forumSlug string, params utilities.ArrayOutParams) (domain.UserArray, error) {
+func (u *forumUsecase) GetUsers(forumSlug string, params utilities.ArrayOutParams) (domain.UserArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, usersCursorScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
 	forumExists, err := u.ForumExists(forumSlug)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	} else if !forumExists {
-		return nil, forum.NotFound
+		return nil, nil, forum.NotFound
 	}
 
-	query, args, err := generateUserRequest(forumSlug, params)
+	query, args, err := generateUserRequest(forumSlug, params, cursor)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rows, err := u.DB.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	resUsers := make(domain.UserArray, 0)
 	for rows.Next() {
 		var currentUser domain.User
-		if err = rows.Scan(&currentUser.Nickname,
-			&currentUser.Fullname,
-			&currentUser.About,
-			&currentUser.Email); err != nil {
-			return nil, err
+		if err = user.Scan(rows, &currentUser); err != nil {
+			return nil, nil, err
 		}
 		resUsers = append(resUsers, currentUser)
 	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
 
-	return resUsers, nil
+	desc := params.Desc
+	if cursor != nil {
+		desc = cursor.Desc
+		if cursor.Backward {
+			for i, j := 0, len(resUsers)-1; i < j; i, j = i+1, j-1 {
+				resUsers[i], resUsers[j] = resUsers[j], resUsers[i]
+			}
+		}
+	}
+	page := &utilities.Page{}
+	if len(resUsers) != 0 {
+		page = utilities.NewPage(usersCursorScope, desc, cursor, len(resUsers), params.Limit,
+			[]string{resUsers[0].Nickname}, []string{resUsers[len(resUsers)-1].Nickname})
+	}
+	return resUsers, page, nil
 }
 
-func generateForumThreadsQuery(forum string, params utilities.ArrayOutParams) (string, []interface{}, error) {
-	req := psql.Select("id, title, author, forum, message, slug, created, votes").From("threads").
+func generateForumThreadsQuery(forum string, params utilities.ArrayOutParams, cursor *utilities.Cursor) (string, []interface{}, error) {
+	req := psql.Select(thread.Columns).From("threads").
 		Where(sq.Eq{"forum": forum})
-	if params.Desc {
-		if params.Since != "" {
-			req = req.Where(sq.LtOrEq{"created": params.Since})
-		}
-		req = req.OrderBy("created desc")
-	} else {
-		if params.Since != "" {
-			req = req.Where(sq.GtOrEq{"created": params.Since})
-		}
-		req = req.OrderBy("created")
+	if params.Tag != "" {
+		req = req.Where("tags @> array[?]", strings.ToLower(strings.TrimSpace(params.Tag)))
 	}
-	req = req.Limit(uint64(params.Limit))
-	return req.ToSql()
+	return thread.ApplyListing(req, params, cursor).ToSql()
 }
 
-func (u *forumUsecase) GetThreads(forumSlug string, params utilities.ArrayOutParams) (domain.ThreadArray, error) {
+// GetThreads lists threads of the forum with the read state of the viewer, if there is one
+func (u *forumUsecase) GetThreads(forumSlug string, viewer string, params utilities.ArrayOutParams) (domain.ThreadArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, thread.CursorScope(threadsCursorScope, params), thread.CursorKeyLen)
+	if err != nil {
+		return nil, nil, err
+	}
 	forumExists, err := u.ForumExists(forumSlug)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	} else if !forumExists {
-		return nil, forum.NotFound
+		return nil, nil, forum.NotFound
 	}
 
-	getThreadsQuery, args, err := generateForumThreadsQuery(forumSlug, params)
-	log.Info(getThreadsQuery)
+	getThreadsQuery, args, err := generateForumThreadsQuery(forumSlug, params, cursor)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rows, err := u.DB.Query(getThreadsQuery, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	resThreads, err := thread.ScanRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	listed := make([]*domain.Thread, 0, len(resThreads))
+	for i := range resThreads {
+		listed = append(listed, &resThreads[i])
+	}
+	if err = u.TUCase.SetUnread(viewer, listed...); err != nil {
+		return nil, nil, err
+	}
+	return resThreads, thread.ListingPage(threadsCursorScope, resThreads, params, cursor), nil
+}
+
+// MarkForumRead moves read markers of the viewer in every thread of the forum up to their last posts
+func (u *forumUsecase) MarkForumRead(slug string, viewer string) (*domain.Forum, error) {
+	forumDetails, err := u.GetForumDetails(slug)
 	if err != nil {
 		return nil, err
 	}
+	query := "insert into read_markers(thread, username, last_read) " +
+		"select id, $2::citext, last_post_id from threads where forum = $1 and last_post_id is not null " +
+		"on conflict (username, thread) do update set last_read = greatest(read_markers.last_read, excluded.last_read);"
+	if _, err = u.DB.Exec(query, forumDetails.Slug, viewer); err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+			return nil, user.NotExistsError
+		}
+		return nil, err
+	}
+	return forumDetails, nil
+}
+
+func (u *forumUsecase) GetTags(forumSlug string, params utilities.ArrayOutParams) (domain.TagCountArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, tagsCursorScope, 2)
+	if err != nil {
+		return nil, nil, err
+	}
+	forumExists, err := u.ForumExists(forumSlug)
+	if err != nil {
+		return nil, nil, err
+	} else if !forumExists {
+		return nil, nil, forum.NotFound
+	}
+
+	// tags go by count desc and tag asc, a backward page inverts both
+	query := "select tag, count(*) from threads, unnest(tags) tag where forum = $1 group by tag"
+	args := []interface{}{forumSlug, params.Limit}
+	order := " order by count(*) desc, tag asc"
+	if cursor != nil {
+		args = append(args, cursor.Key[0], cursor.Key[1])
+		if cursor.Backward {
+			query += " having count(*) > $3::bigint or (count(*) = $3::bigint and tag < $4)"
+			order = " order by count(*) asc, tag desc"
+		} else {
+			query += " having count(*) < $3::bigint or (count(*) = $3::bigint and tag > $4)"
+		}
+	}
+	query += order + " limit $2;"
+
+	rows, err := u.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
 	defer rows.Close()
 
-	// id, title, author, message, forum, votes, slug, created
-	resThreads := make(domain.ThreadArray, 0)
+	resTags := make(domain.TagCountArray, 0)
 	for rows.Next() {
-		var currentThread domain.Thread
-		//var created *strfmt.DateTime
-		var slug *string
-		err := rows.Scan(&currentThread.ID, &currentThread.Title, &currentThread.Author, &currentThread.Forum, &currentThread.Message, &slug, &currentThread.Created, &currentThread.Votes)
-		if err != nil {
-			return nil, err
-		}
-		//if created != nil {
-		//	currentThread.Created = *created
-		//}
-		if slug != nil {
-			currentThread.Slug = *slug
+		var currentTag domain.TagCount
+		if err = rows.Scan(&currentTag.Tag, &currentTag.Count); err != nil {
+			return nil, nil, err
 		}
-		resThreads = append(resThreads, currentThread)
+		resTags = append(resTags, currentTag)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
 	}
 
-	return resThreads, nil
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(resTags)-1; i < j; i, j = i+1, j-1 {
+			resTags[i], resTags[j] = resTags[j], resTags[i]
+		}
+	}
+	page := &utilities.Page{}
+	if len(resTags) != 0 {
+		first, last := resTags[0], resTags[len(resTags)-1]
+		page = utilities.NewPage(tagsCursorScope, false, cursor, len(resTags), params.Limit,
+			[]string{strconv.Itoa(int(first.Count)), first.Tag}, []string{strconv.Itoa(int(last.Count)), last.Tag})
+	}
+	return resTags, page, nil
 }
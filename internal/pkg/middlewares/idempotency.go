@@ -0,0 +1,166 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/jackc/pgx"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"strings"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyMaxLength  = 255
+	// an expired key is taken over as if it was never used, the expiration job may not have removed it yet
+	claimIdempotencyKeyQuery = "insert into idempotency_keys(scope, key, fingerprint) values ($1, $2, $3) " +
+		"on conflict (scope, key) do update set fingerprint = excluded.fingerprint, status = null, content_type = null, " +
+		"etag = null, location = null, link = null, response = null, created = now() where idempotency_keys.created < $4;"
+	getIdempotencyKeyQuery = "select fingerprint, status, content_type, etag, location, link, response from idempotency_keys " +
+		"where scope = $1 and key = $2 and created >= $3;"
+	storeIdempotencyKeyQuery = "update idempotency_keys set status = $3, content_type = $4, etag = $5, location = $6, link = $7, response = $8 " +
+		"where scope = $1 and key = $2;"
+	deleteIdempotencyKeyQuery = "delete from idempotency_keys where scope = $1 and key = $2;"
+	expireIdempotencyKeyQuery = "delete from idempotency_keys where created < $1;"
+	// service routes manage the whole database, replaying them makes no sense
+	serviceRoutesPrefix = "/api/service/"
+)
+
+// Idempotency replays the stored response of a write request repeated with the same Idempotency-Key
+// within IdempotencyTTL, with its ETag, Location and Link headers.
+// Keys are scoped by the method, the path and the client, so clients can't collide on them.
+// The first request claims the key before it runs, so a concurrent repeat is rejected instead of running twice.
+// A key sent with another request is rejected with 422, server errors are not stored so that they can be retried.
+func Idempotency(db *pgx.ConnPool, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		key := string(ctx.Request.Header.Peek(IdempotencyKeyHeader))
+		if key == "" || !(ctx.IsPost() || ctx.IsDelete()) || strings.HasPrefix(string(ctx.Path()), serviceRoutesPrefix) {
+			next(ctx)
+			return
+		}
+		if len(key) > idempotencyKeyMaxLength {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONMessage("idempotency key is too long"))
+			return
+		}
+		scope := keyScope(ctx)
+		fingerprint := requestFingerprint(ctx)
+
+		tag, err := db.Exec(claimIdempotencyKeyQuery, scope, key, fingerprint, time.Now().Add(-constants.IdempotencyTTL))
+		if err != nil {
+			log.WithError(err).Error("idempotency key claim error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+		if tag.RowsAffected() == 0 {
+			replayIdempotentResponse(ctx, db, scope, key, fingerprint)
+			return
+		}
+
+		// a panicking handler must not leave the key claimed until it expires
+		defer func() {
+			if r := recover(); r != nil {
+				if _, err := db.Exec(deleteIdempotencyKeyQuery, scope, key); err != nil {
+					log.WithError(err).Error("idempotency key release error")
+				}
+				panic(r)
+			}
+		}()
+		next(ctx)
+
+		if ctx.Response.StatusCode() >= fasthttp.StatusInternalServerError {
+			_, err = db.Exec(deleteIdempotencyKeyQuery, scope, key)
+		} else {
+			_, err = db.Exec(storeIdempotencyKeyQuery, scope, key, ctx.Response.StatusCode(),
+				string(ctx.Response.Header.ContentType()), responseHeader(ctx, fasthttp.HeaderETag),
+				responseHeader(ctx, fasthttp.HeaderLocation), responseHeader(ctx, fasthttp.HeaderLink), ctx.Response.Body())
+		}
+		if err != nil {
+			log.WithError(err).Error("idempotency key store error")
+		}
+	}
+}
+
+// ExpireIdempotencyKeys removes keys older than IdempotencyTTL in the background
+func ExpireIdempotencyKeys(db *pgx.ConnPool) {
+	for range time.Tick(constants.IdempotencyExpiryInterval) {
+		if _, err := db.Exec(expireIdempotencyKeyQuery, time.Now().Add(-constants.IdempotencyTTL)); err != nil {
+			log.WithError(err).Error("idempotency keys expiration error")
+		}
+	}
+}
+
+func replayIdempotentResponse(ctx *fasthttp.RequestCtx, db *pgx.ConnPool, scope string, key string, fingerprint []byte) {
+	var storedFingerprint, response []byte
+	var status *int32
+	var contentType, etag, location, link *string
+	err := db.QueryRow(getIdempotencyKeyQuery, scope, key, time.Now().Add(-constants.IdempotencyTTL)).
+		Scan(&storedFingerprint, &status, &contentType, &etag, &location, &link, &response)
+	if err == pgx.ErrNoRows {
+		// the key was released or expired after the claim failed, the request may be retried
+		utilities.Resp(ctx, fasthttp.StatusConflict,
+			errors.JSONMessage("request with this idempotency key is in progress"))
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("idempotency key get error")
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if !bytes.Equal(storedFingerprint, fingerprint) {
+		utilities.Resp(ctx, fasthttp.StatusUnprocessableEntity,
+			errors.JSONMessage("idempotency key was already used with another request"))
+		return
+	}
+	if status == nil {
+		utilities.Resp(ctx, fasthttp.StatusConflict,
+			errors.JSONMessage("request with this idempotency key is in progress"))
+		return
+	}
+
+	ctx.SetStatusCode(int(*status))
+	if contentType != nil {
+		ctx.SetContentType(*contentType)
+	}
+	for name, value := range map[string]*string{
+		fasthttp.HeaderETag:     etag,
+		fasthttp.HeaderLocation: location,
+		fasthttp.HeaderLink:     link,
+	} {
+		if value != nil {
+			ctx.Response.Header.Set(name, *value)
+		}
+	}
+	ctx.SetBody(response)
+	ctx.Response.Header.Set(IdempotentReplayedHeader, "true")
+}
+
+// responseHeader is the header of the response to store, nil when it is not set
+func responseHeader(ctx *fasthttp.RequestCtx, name string) *string {
+	value := ctx.Response.Header.Peek(name)
+	if len(value) == 0 {
+		return nil
+	}
+	res := string(value)
+	return &res
+}
+
+// keyScope is the method and the path of the request with the client it is sent for
+func keyScope(ctx *fasthttp.RequestCtx) string {
+	return string(ctx.Method()) + " " + string(ctx.Path()) + " " + utilities.Viewer(ctx)
+}
+
+// requestFingerprint tells apart requests sent with the same key
+func requestFingerprint(ctx *fasthttp.RequestCtx) []byte {
+	hash := sha256.New()
+	hash.Write(ctx.Method())
+	hash.Write([]byte{0})
+	hash.Write(ctx.RequestURI())
+	hash.Write([]byte{0})
+	hash.Write(ctx.PostBody())
+	return hash.Sum(nil)
+}
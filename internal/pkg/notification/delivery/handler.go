@@ -0,0 +1,87 @@
+package delivery
+
+import (
+	"github.com/fasthttp/router"
+	"github.com/mailru/easyjson"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/user"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
+)
+
+type notificationHandler struct {
+	notificationUsecase domain.NotificationUsecase
+	postUsecase         domain.PostUsecase
+}
+
+func NewNotificationHandler(r *router.Router, nu domain.NotificationUsecase, pu domain.PostUsecase) {
+	h := notificationHandler{
+		notificationUsecase: nu,
+		postUsecase:         pu,
+	}
+	s := r.Group("/api/user")
+
+	s.GET("/{nickname}/notifications", h.notificationsGetHandler)
+	s.POST("/{nickname}/notifications/read", h.notificationsReadHandler)
+}
+
+func (handler *notificationHandler) notificationsGetHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	notifications, page, err := handler.notificationUsecase.GetNotifications(nickname, ctx.QueryArgs().GetBool("unread"), *params)
+	if err != nil {
+		log.WithError(err).Error("notifications get error")
+		if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		posts := make([]*domain.Post, 0, len(notifications.Notifications))
+		for _, n := range notifications.Notifications {
+			posts = append(posts, n.Post)
+		}
+		if err = handler.postUsecase.AttachHTML(posts); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, notifications)
+}
+
+func (handler *notificationHandler) notificationsReadHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	read := &domain.NotificationsRead{}
+	if len(ctx.PostBody()) != 0 {
+		err := easyjson.Unmarshal(ctx.PostBody(), read)
+		if err != nil {
+			log.WithError(err).Error(errors.JSONUnmarshallError)
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+			return
+		}
+	}
+	if fieldErrors := validation.Struct(read, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	unread, err := handler.notificationUsecase.MarkRead(nickname, read.IDs)
+	if err != nil {
+		log.WithError(err).Error("notifications mark read error")
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, unread)
+}
@@ -0,0 +1,51 @@
+package notification
+
+import (
+	"github.com/jackc/pgx"
+	"technopark-dbms/internal/pkg/domain"
+)
+
+// Notification types, a user gets one notification per post with the type of the highest priority
+const (
+	Reply    = "reply"
+	Mention  = "mention"
+	Activity = "thread_activity"
+)
+
+// notifyQuery picks recipients of every new post: the author of the parent, users mentioned in it
+// and subscribers of the thread. Authors are not notified of their own posts.
+const notifyQuery = `insert into notifications(username, type, post)
+select distinct on (n.post, lower(n.username)) n.username, n.type, n.post
+from (select parent.author::text as username, '` + Reply + `' as type, 1 as priority, c.id as post, c.author as actor
+      from unnest($1::bigint[], $2::bigint[], $3::text[]) c(id, parent, author)
+               join posts parent on parent.id = c.parent
+      union all
+      select m.nickname, '` + Mention + `', 2, m.post, m.actor
+      from unnest($4::bigint[], $5::text[], $6::text[]) m(post, actor, nickname)
+      union all
+      select s.username::text, '` + Activity + `', 3, c.id, c.author
+      from unnest($1::bigint[], $3::text[]) c(id, author)
+               join thread_subscriptions s on s.thread = $7 and s.active) n
+where n.username::citext <> n.actor::citext
+order by n.post, lower(n.username), n.priority;`
+
+// Notify creates notifications of the posts within the transaction that inserts them,
+// the posts must belong to the thread and have their ids and mentions set
+func Notify(tx *pgx.Tx, threadID int32, posts domain.PostArray) error {
+	ids := make([]int64, 0, len(posts))
+	parents := make([]int64, 0, len(posts))
+	authors := make([]string, 0, len(posts))
+	mentionPosts, mentionActors, mentioned := make([]int64, 0), make([]string, 0), make([]string, 0)
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+		parents = append(parents, p.Parent)
+		authors = append(authors, p.Author)
+		for _, nickname := range p.Mentions {
+			mentionPosts = append(mentionPosts, p.ID)
+			mentionActors = append(mentionActors, p.Author)
+			mentioned = append(mentioned, nickname)
+		}
+	}
+	_, err := tx.Exec(notifyQuery, ids, parents, authors, mentionPosts, mentionActors, mentioned, threadID)
+	return err
+}
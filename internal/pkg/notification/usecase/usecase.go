@@ -0,0 +1,147 @@
+package usecase
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx"
+	"strconv"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/user"
+	"technopark-dbms/internal/pkg/utilities"
+)
+
+const (
+	unreadCountQuery    = "select count(*)::integer from notifications where username = $1 and not read;"
+	markAllReadQuery    = "update notifications set read = true where username = $1 and not read;"
+	markReadQuery       = "update notifications set read = true where username = $1 and id = any($2) and not read;"
+	notificationsScope  = "notifications"
+	notificationColumns = post.Columns + ", n.id, n.type, n.read, n.created"
+)
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+type notificationUsecase struct {
+	DB     *pgx.ConnPool
+	UUCase domain.UserUsecase
+}
+
+func NewNotificationUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase) domain.NotificationUsecase {
+	return &notificationUsecase{
+		DB:     db,
+		UUCase: userUsecase,
+	}
+}
+
+// GetNotifications pages over notifications of the user from the newest one along with the unread count,
+// unreadOnly leaves out those already read
+func (u *notificationUsecase) GetNotifications(nickname string, unreadOnly bool, params utilities.ArrayOutParams) (*domain.NotificationList, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, notificationsScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = u.checkUser(nickname); err != nil {
+		return nil, nil, err
+	}
+
+	since, desc, backward := params.Since, true, false
+	if cursor != nil {
+		since, desc, backward = cursor.Key[0], cursor.Desc, cursor.Backward
+	}
+	req := psql.Select(notificationColumns).
+		From("notifications n").
+		Join("posts p on p.id = n.post").
+		Where(sq.Eq{"n.username": nickname})
+	if unreadOnly {
+		req = req.Where("not n.read")
+	}
+	if since != "" {
+		sinceID, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		if desc != backward {
+			req = req.Where(sq.Lt{"n.id": sinceID})
+		} else {
+			req = req.Where(sq.Gt{"n.id": sinceID})
+		}
+	}
+	if desc != backward {
+		req = req.OrderBy("n.id desc")
+	} else {
+		req = req.OrderBy("n.id asc")
+	}
+	query, args, err := req.Limit(uint64(params.Limit)).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := u.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	res := &domain.NotificationList{Notifications: make(domain.NotificationArray, 0)}
+	posts := make([]*domain.Post, 0)
+	for rows.Next() {
+		n := domain.Notification{Post: &domain.Post{}}
+		if err = post.Scan(rows, n.Post, &n.ID, &n.Type, &n.Read, &n.Created); err != nil {
+			return nil, nil, err
+		}
+		res.Notifications = append(res.Notifications, n)
+		posts = append(posts, n.Post)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+	if err = post.LoadQuoted(u.DB, posts); err != nil {
+		return nil, nil, err
+	}
+	if err = u.DB.QueryRow(unreadCountQuery, nickname).Scan(&res.Unread); err != nil {
+		return nil, nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(res.Notifications)-1; i < j; i, j = i+1, j-1 {
+			res.Notifications[i], res.Notifications[j] = res.Notifications[j], res.Notifications[i]
+		}
+	}
+	page := &utilities.Page{}
+	if count := len(res.Notifications); count != 0 {
+		page = utilities.NewPage(notificationsScope, desc, cursor, count, params.Limit,
+			[]string{strconv.FormatInt(res.Notifications[0].ID, 10)}, []string{strconv.FormatInt(res.Notifications[count-1].ID, 10)})
+	}
+	return res, page, nil
+}
+
+// MarkRead marks the notifications of the user as read, all of them when ids are empty.
+// Ids of notifications of other users are ignored.
+func (u *notificationUsecase) MarkRead(nickname string, ids []int64) (*domain.UnreadCount, error) {
+	if err := u.checkUser(nickname); err != nil {
+		return nil, err
+	}
+	var err error
+	if len(ids) == 0 {
+		_, err = u.DB.Exec(markAllReadQuery, nickname)
+	} else {
+		_, err = u.DB.Exec(markReadQuery, nickname, ids)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := &domain.UnreadCount{}
+	if err = u.DB.QueryRow(unreadCountQuery, nickname).Scan(&res.Unread); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (u *notificationUsecase) checkUser(nickname string) error {
+	exists, err := u.UUCase.UserExists(nickname, "")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return user.NotExistsError
+	}
+	return nil
+}
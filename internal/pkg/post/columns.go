@@ -0,0 +1,70 @@
+package post
+
+import (
+	"fmt"
+	"technopark-dbms/internal/pkg/domain"
+)
+
+// Columns are the post columns Scan expects, p is the posts alias.
+// Rendered messages are left out, AttachHTML reads them for the clients asking for them.
+const Columns = "p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created, p.votes, p.mentions, p.quotes, p.version"
+
+// ScoreOrder is the order_by value that sorts thread posts by votes within flat and tree sorts
+const ScoreOrder = "score"
+
+type row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan reads Columns into the post, extra destinations take the columns selected after them
+func Scan(r row, p *domain.Post, extra ...interface{}) error {
+	dest := []interface{}{&p.ID, &p.Parent, &p.Author, &p.Message, &p.IsEdited, &p.Forum, &p.Thread, &p.Created, &p.Votes, &p.Mentions, &p.Quotes, &p.Version}
+	return r.Scan(append(dest, extra...)...)
+}
+
+// DepthLimit cuts a tree of posts where length, the expression of the p post position, exceeds the limit.
+// It appends the limit to query args and returns the condition with the column counting replies of posts at the cut.
+// Zero limit keeps the whole tree and the column is zero.
+func DepthLimit(length string, limit int, args []interface{}) (string, string, []interface{}) {
+	if limit == 0 {
+		return "", "0", args
+	}
+	args = append(args, limit)
+	arg := fmt.Sprintf("$%d", len(args))
+	return " and " + length + " <= " + arg,
+		"case when " + length + " = " + arg + " then (select count(*)::integer from posts c where c.thread = p.thread and c.parent = p.id) else 0 end",
+		args
+}
+
+// SetRepliesCount marks a post cut off by a depth limit
+func SetRepliesCount(p *domain.Post, count int32) {
+	p.RepliesCount, p.HasMore = count, count != 0
+}
+
+// Nest puts posts of a page in the tree order under their parents, posts with parents outside the page become roots
+func Nest(posts domain.PostArray) domain.PostArray {
+	onPage := make(map[int64]bool, len(posts))
+	for _, p := range posts {
+		onPage[p.ID] = true
+	}
+	roots := make([]int, 0)
+	children := make(map[int64][]int)
+	for i, p := range posts {
+		if p.Parent != 0 && onPage[p.Parent] {
+			children[p.Parent] = append(children[p.Parent], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+	var nest func(indexes []int) domain.PostArray
+	nest = func(indexes []int) domain.PostArray {
+		res := make(domain.PostArray, 0, len(indexes))
+		for _, i := range indexes {
+			p := posts[i]
+			p.Children = nest(children[p.ID])
+			res = append(res, p)
+		}
+		return res
+	}
+	return nest(roots)
+}
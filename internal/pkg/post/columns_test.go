@@ -0,0 +1,84 @@
+package post
+
+import (
+	"reflect"
+	"technopark-dbms/internal/pkg/domain"
+	"testing"
+)
+
+// shape writes a nested page as ids with children in brackets
+func shape(posts domain.PostArray) []interface{} {
+	res := make([]interface{}, 0, len(posts))
+	for _, p := range posts {
+		if len(p.Children) == 0 {
+			res = append(res, p.ID)
+		} else {
+			res = append(res, p.ID, shape(p.Children))
+		}
+	}
+	return res
+}
+
+func TestNest(t *testing.T) {
+	tests := []struct {
+		name  string
+		posts domain.PostArray
+		want  []interface{}
+	}{
+		{"empty", domain.PostArray{}, []interface{}{}},
+		{"roots", domain.PostArray{{ID: 1}, {ID: 2}}, []interface{}{int64(1), int64(2)}},
+		{"tree", domain.PostArray{{ID: 1}, {ID: 2, Parent: 1}, {ID: 3, Parent: 2}, {ID: 4, Parent: 1}, {ID: 5}},
+			[]interface{}{int64(1), []interface{}{int64(2), []interface{}{int64(3)}, int64(4)}, int64(5)}},
+		{"parent off the page", domain.PostArray{{ID: 2, Parent: 1}, {ID: 3, Parent: 2}, {ID: 4, Parent: 1}},
+			[]interface{}{int64(2), []interface{}{int64(3)}, int64(4)}},
+		{"page order kept", domain.PostArray{{ID: 1}, {ID: 3, Parent: 1}, {ID: 2, Parent: 1}},
+			[]interface{}{int64(1), []interface{}{int64(3), int64(2)}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := shape(Nest(test.posts)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Nest() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int
+		args          []interface{}
+		wantCondition string
+		wantColumn    string
+		wantArgs      []interface{}
+	}{
+		{"no limit", 0, []interface{}{1}, "", "0", []interface{}{1}},
+		{"limit", 2, []interface{}{1, 10}, " and depth <= $3",
+			"case when depth = $3 then (select count(*)::integer from posts c where c.thread = p.thread and c.parent = p.id) else 0 end",
+			[]interface{}{1, 10, 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition, column, args := DepthLimit("depth", test.limit, test.args)
+			if condition != test.wantCondition {
+				t.Errorf("DepthLimit() condition = %q, want %q", condition, test.wantCondition)
+			}
+			if column != test.wantColumn {
+				t.Errorf("DepthLimit() column = %q, want %q", column, test.wantColumn)
+			}
+			if !reflect.DeepEqual(args, test.wantArgs) {
+				t.Errorf("DepthLimit() args = %v, want %v", args, test.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSetRepliesCount(t *testing.T) {
+	for _, count := range []int32{0, 3} {
+		p := &domain.Post{}
+		SetRepliesCount(p, count)
+		if p.RepliesCount != count || p.HasMore != (count != 0) {
+			t.Errorf("SetRepliesCount(%d) = %d, %t", count, p.RepliesCount, p.HasMore)
+		}
+	}
+}
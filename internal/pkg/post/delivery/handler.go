@@ -3,6 +3,7 @@ package delivery
 import (
 	"encoding/json"
 	"github.com/fasthttp/router"
+	"github.com/mailru/easyjson"
 	log "github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 	"strconv"
@@ -10,7 +11,10 @@ import (
 	"technopark-dbms/internal/pkg/domain"
 	"technopark-dbms/internal/pkg/errors"
 	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
 )
 
 type postHandler struct {
@@ -25,6 +29,15 @@ func NewPostHandler(r *router.Router, pu domain.PostUsecase) {
 
 	s.GET("/{id:[0-9]+}/details", h.postGetDetailsHandler)
 	s.POST("/{id:[0-9]+}/details", h.postUpdateDetailsHandler)
+	s.POST("/{id:[0-9]+}/split", h.postSplitHandler)
+	s.POST("/{id:[0-9]+}/vote", h.postVoteHandler)
+	s.DELETE("/{id:[0-9]+}/vote", h.postRetractVoteHandler)
+	s.GET("/{id:[0-9]+}/replies", h.postGetRepliesHandler)
+	s.GET("/{id:[0-9]+}/context", h.postGetContextHandler)
+	s.GET("/{id:[0-9]+}/quoted-by", h.postGetQuotedByHandler)
+
+	r.POST("/api/posts/lookup", h.postsLookupHandler)
+	r.GET("/api/user/{nickname}/mentions", h.userMentionsHandler)
 }
 
 func (handler *postHandler) postGetDetailsHandler(ctx *fasthttp.RequestCtx) {
@@ -51,8 +64,15 @@ func (handler *postHandler) postGetDetailsHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	postFull := domain.PostFull{foundPost, foundForum, foundThread, foundUser}
-	utilities.Resp(ctx, fasthttp.StatusOK, postFull)
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML([]*domain.Post{foundPost}); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	postFull := domain.PostFull{Post: foundPost, Forum: foundForum, Thread: foundThread, User: foundUser}
+	utilities.RespTagged(ctx, foundPost.Version, postFull)
 }
 
 func (handler *postHandler) postUpdateDetailsHandler(ctx *fasthttp.RequestCtx) {
@@ -71,15 +91,322 @@ func (handler *postHandler) postUpdateDetailsHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedPost, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+	version, err := utilities.IfMatchVersion(ctx)
+	if err != nil {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(domain.FieldErrorArray{
+			{Field: fasthttp.HeaderIfMatch, Message: err.Error()}}))
+		return
+	}
+	parsedPost.Version = version
+
 	foundPost, err := handler.postUsecase.UpdatePostDetails(postId, *parsedPost)
 	if err != nil {
 		log.WithError(err).Error("forum update details error")
 		if err == post.NotFoundError {
 			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
 			return
+		} else if err == utilities.PreconditionFailed {
+			utilities.Resp(ctx, fasthttp.StatusPreconditionFailed, errors.JSONErrorMessage(err))
+			return
 		}
 		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONEncodeErrorMessage)
 		return
 	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML([]*domain.Post{foundPost}); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.SetETag(ctx, foundPost.Version)
 	utilities.Resp(ctx, fasthttp.StatusOK, foundPost)
 }
+
+func (handler *postHandler) postSplitHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+
+	parsedThread := &domain.Thread{}
+	if len(ctx.PostBody()) != 0 {
+		err = easyjson.Unmarshal(ctx.PostBody(), parsedThread)
+		if err != nil {
+			log.WithError(err).Error(errors.JSONUnmarshallError)
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+			return
+		}
+	}
+
+	if fieldErrors := validation.Struct(parsedThread, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	createdThread, err := handler.postUsecase.SplitPost(postId, *parsedThread)
+	if err != nil {
+		log.WithError(err).Error("post split error")
+		switch err {
+		case post.NotFoundError, thread.NotFound, thread.AuthorNotExists:
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+		case thread.AlreadyExists:
+			utilities.Resp(ctx, fasthttp.StatusConflict, errors.JSONErrorMessage(err))
+		default:
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		}
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusCreated, createdThread)
+}
+
+func (handler *postHandler) postVoteHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+
+	parsedVote := &domain.Vote{}
+	err = easyjson.Unmarshal(ctx.PostBody(), parsedVote)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return
+	}
+	if fieldErrors := validation.Struct(parsedVote, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	votedPost, err := handler.postUsecase.CreatePostVote(postId, *parsedVote)
+	if err != nil {
+		log.WithError(err).Error("post vote error")
+		if err == post.NotFoundError || err == post.VoterNotExists {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML([]*domain.Post{votedPost}); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, votedPost)
+}
+
+func (handler *postHandler) postRetractVoteHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+	nickname := string(ctx.QueryArgs().Peek("nickname"))
+	if fieldErrors := validation.Var("nickname", nickname, "required,nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	votedPost, err := handler.postUsecase.CreatePostVote(postId, domain.Vote{Nickname: nickname})
+	if err != nil {
+		log.WithError(err).Error("post vote retraction error")
+		if err == post.NotFoundError || err == post.VoterNotExists {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML([]*domain.Post{votedPost}); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, votedPost)
+}
+
+func (handler *postHandler) postsLookupHandler(ctx *fasthttp.RequestCtx) {
+	lookup := &domain.PostLookup{}
+	err := easyjson.Unmarshal(ctx.PostBody(), lookup)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return
+	}
+
+	fieldErrors := validation.Struct(lookup, validation.Create)
+	var userRelated, forumRelated, threadRelated bool
+	for _, related := range lookup.Related {
+		fieldErrors = append(fieldErrors, validation.Var("related", related, "oneof=user forum thread")...)
+		userRelated = userRelated || related == "user"
+		forumRelated = forumRelated || related == "forum"
+		threadRelated = threadRelated || related == "thread"
+	}
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	res, err := handler.postUsecase.LookupPosts(lookup.IDs, userRelated, forumRelated, threadRelated)
+	if err != nil {
+		log.WithError(err).Error("posts lookup error")
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(res.Posts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, res)
+}
+
+func (handler *postHandler) postGetRepliesHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundPosts, page, err := handler.postUsecase.GetPostReplies(postId, *params)
+	if err != nil {
+		log.WithError(err).Error("post get replies error")
+		if err == post.NotFoundError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		} else if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(foundPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	if ctx.QueryArgs().GetBool("nested") {
+		foundPosts = post.Nest(foundPosts)
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundPosts)
+}
+
+func (handler *postHandler) postGetContextHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+
+	foundPosts, err := handler.postUsecase.GetPostContext(postId)
+	if err != nil {
+		log.WithError(err).Error("post get context error")
+		if err == post.NotFoundError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(foundPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, foundPosts)
+}
+
+func (handler *postHandler) userMentionsHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundPosts, page, err := handler.postUsecase.GetUserMentions(nickname, *params)
+	if err != nil {
+		log.WithError(err).Error("user get mentions error")
+		if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(foundPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundPosts)
+}
+
+func (handler *postHandler) postGetQuotedByHandler(ctx *fasthttp.RequestCtx) {
+	postId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundPosts, page, err := handler.postUsecase.GetQuotedBy(postId, *params)
+	if err != nil {
+		log.WithError(err).Error("post get quoted by error")
+		if err == post.NotFoundError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		} else if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(foundPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundPosts)
+}
@@ -5,4 +5,6 @@ import "errors"
 var (
 	NotFoundError      = errors.New("post not found")
 	InvalidParentError = errors.New("parent post was created in another thread")
+	VoterNotExists     = errors.New("voter does not exist")
+	QuotedNotFound     = errors.New("quoted post not found")
 )
@@ -0,0 +1,210 @@
+package post
+
+import (
+	"github.com/jackc/pgx"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"technopark-dbms/internal/pkg/domain"
+	"unicode/utf8"
+)
+
+var (
+	linkPattern     = regexp.MustCompile(`^\[([^\[\]]*)\]\(([^()\s]+)\)`)
+	languagePattern = regexp.MustCompile(`^[A-Za-z0-9_+#.-]+$`)
+)
+
+// RenderMessage renders the Markdown subset of a message into HTML: fenced code blocks, quotes,
+// paragraphs with line breaks, code spans, links, strong and emphasis. The rest is text.
+// All text is escaped and links lead to http, https and mailto only, so the HTML is safe to embed as is.
+func RenderMessage(message string) string {
+	b := &strings.Builder{}
+	renderBlocks(b, strings.Split(strings.ReplaceAll(message, "\r\n", "\n"), "\n"))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+const htmlQuery = "select id, message_html from posts where id = any($1);"
+
+// AttachHTML sets the rendered messages cached with the posts, they are sent on request only.
+// Posts stored before rendering was added have no cached HTML and are rendered on the fly.
+func AttachHTML(db *pgx.ConnPool, posts []*domain.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	rows, err := db.Query(htmlQuery, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	cached := make(map[int64]string, len(posts))
+	for rows.Next() {
+		var id int64
+		var messageHTML string
+		if err = rows.Scan(&id, &messageHTML); err != nil {
+			return err
+		}
+		cached[id] = messageHTML
+	}
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+	for _, p := range posts {
+		if p.MessageHTML = cached[p.ID]; p.MessageHTML == "" {
+			p.MessageHTML = RenderMessage(p.Message)
+		}
+	}
+	return nil
+}
+
+// Refs points at the posts and their children, for AttachHTML to fill the posts of a page in place
+func Refs(posts domain.PostArray) []*domain.Post {
+	res := make([]*domain.Post, 0, len(posts))
+	for i := range posts {
+		res = append(res, &posts[i])
+		res = append(res, Refs(posts[i].Children)...)
+	}
+	return res
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	paragraph := make([]string, 0)
+	flush := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		b.WriteString("<p>")
+		for i, line := range paragraph {
+			if i != 0 {
+				b.WriteString("<br>\n")
+			}
+			renderInline(b, strings.TrimSpace(line))
+		}
+		b.WriteString("</p>\n")
+		paragraph = paragraph[:0]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			language := strings.Fields(strings.TrimPrefix(trimmed, "```"))
+			code := make([]string, 0)
+			// a block without the closing fence runs to the end of the message
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+			b.WriteString("<pre><code")
+			if len(language) != 0 && languagePattern.MatchString(language[0]) {
+				b.WriteString(` class="language-` + language[0] + `"`)
+			}
+			b.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			quoted := make([]string, 0)
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			i--
+			b.WriteString("<blockquote>\n")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>\n")
+		case trimmed == "":
+			flush()
+		default:
+			paragraph = append(paragraph, lines[i])
+		}
+	}
+	flush()
+}
+
+func renderInline(b *strings.Builder, text string) {
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch rest[0] {
+		case '`':
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				b.WriteString("<code>" + html.EscapeString(rest[1:1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case '[':
+			if match := linkPattern.FindStringSubmatch(rest); match != nil {
+				if href, ok := safeURL(match[2]); ok {
+					b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">`)
+					renderInline(b, match[1])
+					b.WriteString("</a>")
+				} else {
+					renderInline(b, match[1])
+				}
+				i += len(match[0])
+				continue
+			}
+		case '*', '_':
+			if n := renderEmphasis(b, text, i); n != 0 {
+				i += n
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(rest)
+		b.WriteString(html.EscapeString(rest[:size]))
+		i += size
+	}
+}
+
+// renderEmphasis renders the emphasis opening at text[i], doubled delimiters are strong.
+// It returns the length of the emphasis, 0 when the delimiter is text, like underscores inside snake_case words.
+func renderEmphasis(b *strings.Builder, text string, i int) int {
+	c := text[i]
+	delimiter, tag := text[i:i+1], "em"
+	if strings.HasPrefix(text[i+1:], delimiter) {
+		delimiter, tag = text[i:i+2], "strong"
+	}
+	start := i + len(delimiter)
+	if c == '_' && i > 0 && isWordByte(text[i-1]) || start >= len(text) || text[start] == ' ' {
+		return 0
+	}
+
+	for from := start; ; {
+		j := strings.Index(text[from:], delimiter)
+		if j < 0 {
+			return 0
+		}
+		end := from + j
+		after := end + len(delimiter)
+		if len(delimiter) == 1 && after < len(text) && text[after] == c {
+			// a strong delimiter inside the emphasis
+			from = after + 1
+			continue
+		}
+		if end == start || text[end-1] == ' ' || c == '_' && after < len(text) && isWordByte(text[after]) {
+			from = end + 1
+			continue
+		}
+		b.WriteString("<" + tag + ">")
+		renderInline(b, text[start:end])
+		b.WriteString("</" + tag + ">")
+		return after - i
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= utf8.RuneSelf
+}
+
+func safeURL(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "mailto":
+		return u.String(), true
+	}
+	return "", false
+}
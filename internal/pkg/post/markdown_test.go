@@ -0,0 +1,71 @@
+package post
+
+import (
+	"technopark-dbms/internal/pkg/domain"
+	"testing"
+)
+
+func TestRenderMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"empty", "", ""},
+		{"paragraph", "hello", "<p>hello</p>"},
+		{"line break", "a\nb", "<p>a<br>\nb</p>"},
+		{"windows line break", "a\r\nb", "<p>a<br>\nb</p>"},
+		{"paragraphs", "a\n\nb", "<p>a</p>\n<p>b</p>"},
+		{"escaped tags", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"strong and emphasis", "**bold** and *em*", "<p><strong>bold</strong> and <em>em</em></p>"},
+		{"underscore emphasis", "_em_", "<p><em>em</em></p>"},
+		{"snake case", "snake_case_name", "<p>snake_case_name</p>"},
+		{"unclosed strong", "**unclosed", "<p>**unclosed</p>"},
+		{"list marker", "* not em", "<p>* not em</p>"},
+		{"unicode emphasis", "мир *тест*", "<p>мир <em>тест</em></p>"},
+		{"code span", "`a<b`", "<p><code>a&lt;b</code></p>"},
+		{"code span keeps emphasis", "`*a*`", "<p><code>*a*</code></p>"},
+		{"link", "[site](https://example.com)",
+			`<p><a href="https://example.com" rel="nofollow noopener noreferrer">site</a></p>`},
+		{"link with emphasis and query", "[a *b*](http://x.y/?q=1&r=2)",
+			`<p><a href="http://x.y/?q=1&amp;r=2" rel="nofollow noopener noreferrer">a <em>b</em></a></p>`},
+		{"mailto link", "[me](mailto:me@example.com)",
+			`<p><a href="mailto:me@example.com" rel="nofollow noopener noreferrer">me</a></p>`},
+		{"javascript link", "[x](javascript:alert)", "<p>x</p>"},
+		{"data link", "[x](data:text/html,hi)", "<p>x</p>"},
+		{"quote", "> quoted\n> more", "<blockquote>\n<p>quoted<br>\nmore</p>\n</blockquote>"},
+		{"nested quote", "> > deep", "<blockquote>\n<blockquote>\n<p>deep</p>\n</blockquote>\n</blockquote>"},
+		{"code block", "```go\nx := 1 < 2\n```", `<pre><code class="language-go">x := 1 &lt; 2</code></pre>`},
+		{"code block keeps markdown", "```\n**a**\n```", "<pre><code>**a**</code></pre>"},
+		{"unclosed code block", "```\ncode", "<pre><code>code</code></pre>"},
+		{"code block language injection", "```bad\"lang\ncode", "<pre><code>code</code></pre>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := RenderMessage(test.message); got != test.want {
+				t.Errorf("RenderMessage(%q) = %q, want %q", test.message, got, test.want)
+			}
+		})
+	}
+}
+
+func TestRefs(t *testing.T) {
+	posts := domain.PostArray{
+		{ID: 1, Children: domain.PostArray{{ID: 2, Children: domain.PostArray{{ID: 3}}}}},
+		{ID: 4},
+	}
+	refs := Refs(posts)
+	want := []int64{1, 2, 3, 4}
+	if len(refs) != len(want) {
+		t.Fatalf("Refs returned %d posts, want %d", len(refs), len(want))
+	}
+	for i, p := range refs {
+		if p.ID != want[i] {
+			t.Errorf("Refs()[%d].ID = %d, want %d", i, p.ID, want[i])
+		}
+	}
+	refs[2].MessageHTML = "<p>x</p>"
+	if posts[0].Children[0].Children[0].MessageHTML != "<p>x</p>" {
+		t.Error("Refs does not point at the posts in place")
+	}
+}
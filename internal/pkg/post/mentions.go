@@ -0,0 +1,86 @@
+package post
+
+import (
+	"github.com/jackc/pgx"
+	"regexp"
+	"strings"
+	"technopark-dbms/internal/pkg/domain"
+)
+
+// mentionPattern matches @nickname not preceded by a nickname character, so emails are not mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_.@])@([A-Za-z0-9_.]+)`)
+
+// Mentions returns nicknames mentioned in the message in the order of first mention.
+// A trailing dot ends a sentence rather than the nickname, nicknames differing in case are the same.
+func Mentions(message string) []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(message, -1) {
+		nickname := strings.TrimRight(match[1], ".")
+		key := strings.ToLower(nickname)
+		if nickname == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, nickname)
+	}
+	return res
+}
+
+// ResolveMentions sets mentions of the posts to the users their messages mention, spelled as registered.
+// Mentions of unknown nicknames stay plain text.
+func ResolveMentions(tx *pgx.Tx, posts domain.PostArray) error {
+	nicknames := make([]string, 0)
+	for i := range posts {
+		posts[i].Mentions = Mentions(posts[i].Message)
+		nicknames = append(nicknames, posts[i].Mentions...)
+	}
+	if len(nicknames) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query("select nickname from users where nickname = any($1::text[]::citext[]);", nicknames)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	registered := make(map[string]string)
+	for rows.Next() {
+		var nickname string
+		if err = rows.Scan(&nickname); err != nil {
+			return err
+		}
+		registered[strings.ToLower(nickname)] = nickname
+	}
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for i := range posts {
+		resolved := make([]string, 0, len(posts[i].Mentions))
+		for _, nickname := range posts[i].Mentions {
+			if registeredNickname, ok := registered[strings.ToLower(nickname)]; ok {
+				resolved = append(resolved, registeredNickname)
+			}
+		}
+		posts[i].Mentions = resolved
+	}
+	return nil
+}
+
+// SaveMentions records resolved mentions of the posts, the posts must have their ids set
+func SaveMentions(tx *pgx.Tx, posts domain.PostArray) error {
+	ids, nicknames := make([]int64, 0), make([]string, 0)
+	for _, p := range posts {
+		for _, nickname := range p.Mentions {
+			ids = append(ids, p.ID)
+			nicknames = append(nicknames, nickname)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query := "insert into mentions(post, username) select m.post, m.username from unnest($1::bigint[], $2::text[]) m(post, username) on conflict do nothing;"
+	_, err := tx.Exec(query, ids, nicknames)
+	return err
+}
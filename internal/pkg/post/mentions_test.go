@@ -0,0 +1,34 @@
+package post
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    []string
+	}{
+		{"none", "hello", []string{}},
+		{"single", "hi @alice", []string{"alice"}},
+		{"start of message", "@alice hi", []string{"alice"}},
+		{"order of first mention", "@bob and @alice, @bob again", []string{"bob", "alice"}},
+		{"case insensitive duplicates", "@Alice @alice", []string{"Alice"}},
+		{"trailing dot", "thanks @alice.", []string{"alice"}},
+		{"dot inside nickname", "ask @john.doe about it", []string{"john.doe"}},
+		{"email", "write to me@example.com", []string{}},
+		{"double at", "@@alice", []string{}},
+		{"after punctuation", "(@alice) and \"@bob\"", []string{"alice", "bob"}},
+		{"lone at", "meet @ noon", []string{}},
+		{"only dots", "@... what", []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Mentions(test.message); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Mentions(%q) = %q, want %q", test.message, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package post
+
+import (
+	"github.com/jackc/pgx"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+)
+
+type queryer interface {
+	Query(sql string, args ...interface{}) (*pgx.Rows, error)
+}
+
+// NewQuote shows the post as it is quoted, a long message is cut to an excerpt
+func NewQuote(p *domain.Post) domain.Quote {
+	excerpt := []rune(p.Message)
+	if len(excerpt) > constants.QuoteExcerptLength {
+		excerpt = append(excerpt[:constants.QuoteExcerptLength], '…')
+	}
+	return domain.Quote{ID: p.ID, Author: p.Author, Excerpt: string(excerpt), Thread: p.Thread, Forum: p.Forum}
+}
+
+// LoadQuoted resolves quotes of the posts from the quoted posts as they are now,
+// so a quoted post moved to another thread or edited shows as such
+func LoadQuoted(q queryer, posts []*domain.Post) error {
+	ids := make([]int64, 0)
+	for _, p := range posts {
+		ids = append(ids, p.Quotes...)
+	}
+	quoted, err := loadQuotes(q, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		if len(p.Quotes) == 0 {
+			continue
+		}
+		p.Quoted = make([]domain.Quote, 0, len(p.Quotes))
+		for _, id := range p.Quotes {
+			if quote, found := quoted[id]; found {
+				p.Quoted = append(p.Quoted, quote)
+			}
+		}
+	}
+	return nil
+}
+
+// ResolveQuotes checks in the transaction creating the posts that they quote existing posts and sets what they quote,
+// a post quoted twice by the same post is kept once
+func ResolveQuotes(tx *pgx.Tx, posts domain.PostArray) error {
+	ids := make([]int64, 0)
+	for i := range posts {
+		unique := make([]int64, 0, len(posts[i].Quotes))
+		seen := make(map[int64]bool, len(posts[i].Quotes))
+		for _, id := range posts[i].Quotes {
+			if !seen[id] {
+				seen[id] = true
+				unique = append(unique, id)
+			}
+		}
+		posts[i].Quotes = unique
+		ids = append(ids, unique...)
+	}
+	quoted, err := loadQuotes(tx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range posts {
+		if len(posts[i].Quotes) == 0 {
+			continue
+		}
+		posts[i].Quoted = make([]domain.Quote, 0, len(posts[i].Quotes))
+		for _, id := range posts[i].Quotes {
+			quote, found := quoted[id]
+			if !found {
+				return QuotedNotFound
+			}
+			posts[i].Quoted = append(posts[i].Quoted, quote)
+		}
+	}
+	return nil
+}
+
+// loadQuotes reads the quoted posts in one query, posts that do not exist are missing from the map
+func loadQuotes(q queryer, ids []int64) (map[int64]domain.Quote, error) {
+	quoted := make(map[int64]domain.Quote)
+	if len(ids) == 0 {
+		return quoted, nil
+	}
+	rows, err := q.Query("select id, author, message, thread, forum from posts where id = any($1);", ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p domain.Post
+		if err = rows.Scan(&p.ID, &p.Author, &p.Message, &p.Thread, &p.Forum); err != nil {
+			return nil, err
+		}
+		quoted[p.ID] = NewQuote(&p)
+	}
+	return quoted, rows.Err()
+}
+
+// SaveQuotes records the posts as quoting ones for backlinks, the posts must have their ids set
+func SaveQuotes(tx *pgx.Tx, posts domain.PostArray) error {
+	ids, quoted := make([]int64, 0), make([]int64, 0)
+	for _, p := range posts {
+		for _, id := range p.Quotes {
+			ids = append(ids, p.ID)
+			quoted = append(quoted, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query := "insert into quotes(post, quoted) select q.post, q.quoted from unnest($1::bigint[], $2::bigint[]) q(post, quoted) on conflict do nothing;"
+	_, err := tx.Exec(query, ids, quoted)
+	return err
+}
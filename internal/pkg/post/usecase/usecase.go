@@ -1,12 +1,20 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx"
+	"github.com/jackc/pgx/pgtype"
+	"strconv"
+	"technopark-dbms/internal/pkg/cache"
 	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/forum"
 	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/webhook"
 )
 
 var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
@@ -16,14 +24,14 @@ type postUsecase struct {
 	UUCase domain.UserUsecase
 	FUCase domain.ForumUsecase
 	TUCase domain.ThreadUsecase
+	Cache  *cache.Cache
 }
 
 func (p *postUsecase) GetPostById(id int64) (*domain.Post, error) {
-	query := "select id, parent, author, message, is_edited, forum, thread, created from posts where id = $1"
+	query := "select " + post.Columns + " from posts p where p.id = $1"
 	resPost := &domain.Post{}
 	//var created *strfmt.DateTime
-	err := p.DB.QueryRow(query, id).
-		Scan(&resPost.ID, &resPost.Parent, &resPost.Author, &resPost.Message, &resPost.IsEdited, &resPost.Forum, &resPost.Thread, &resPost.Created)
+	err := post.Scan(p.DB.QueryRow(query, id), resPost)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, post.NotFoundError
@@ -33,12 +41,13 @@ func (p *postUsecase) GetPostById(id int64) (*domain.Post, error) {
 	return resPost, nil
 }
 
-func NewPostUsecase(db *pgx.ConnPool, uUCase domain.UserUsecase, fUCase domain.ForumUsecase, tUCase domain.ThreadUsecase) domain.PostUsecase {
+func NewPostUsecase(db *pgx.ConnPool, uUCase domain.UserUsecase, fUCase domain.ForumUsecase, tUCase domain.ThreadUsecase, c *cache.Cache) domain.PostUsecase {
 	return &postUsecase{
 		DB:     db,
 		UUCase: uUCase,
 		FUCase: fUCase,
 		TUCase: tUCase,
+		Cache:  c,
 	}
 }
 
@@ -47,6 +56,9 @@ func (p *postUsecase) GetPostDetails(id int64, relatedUser bool, relatedForum bo
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
+	if err = post.LoadQuoted(p.DB, []*domain.Post{resPost}); err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	var resForum *domain.Forum
 	var resThread *domain.Thread
@@ -72,22 +84,561 @@ func (p *postUsecase) GetPostDetails(id int64, relatedUser bool, relatedForum bo
 	return resPost, resForum, resThread, resUser, nil
 }
 
+const repliesCursorScope = "post_replies"
+
+// maxWayElement closes the way range of a subtree, every descendant way is between the root way and the root way with it appended
+const maxWayElement = "9223372036854775807"
+
+// GetPostReplies pages over the subtree under the post in the tree order, MaxDepth limits the levels below it.
+// Like in the tree sort of thread posts, the post id is enough to restore the way sort key.
+func (p *postUsecase) GetPostReplies(id int64, params utilities.ArrayOutParams) (domain.PostArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, repliesCursorScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rootWay []int64
+	err = p.DB.QueryRow("select way from posts where id = $1;", id).Scan(&rootWay)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, post.NotFoundError
+		}
+		return nil, nil, err
+	}
+
+	since, desc, backward := params.Since, params.Desc, false
+	if cursor != nil {
+		since, desc, backward = cursor.Key[0], cursor.Desc, cursor.Backward
+	}
+	order, s := "asc", " > "
+	if desc != backward {
+		order, s = "desc", " < "
+	}
+	args := []interface{}{rootWay, params.Limit}
+	condition := ""
+	if since != "" {
+		sinceID, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		args = append(args, sinceID)
+		condition += fmt.Sprintf(" and p.way %s (select way from posts where id = $%d)", s, len(args))
+	}
+	depthLimit := 0
+	if params.MaxDepth != 0 {
+		depthLimit = len(rootWay) + int(params.MaxDepth)
+	}
+	depthCondition, repliesColumn, args := post.DepthLimit("array_length(p.way, 1)", depthLimit, args)
+	query := "select " + post.Columns + ", " + repliesColumn +
+		" from posts p where p.way > $1::bigint[] and p.way < $1::bigint[] || " + maxWayElement + "::bigint" + condition + depthCondition +
+		" order by p.way " + order + " limit $2;"
+
+	rows, err := p.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	resPosts := make(domain.PostArray, 0)
+	for rows.Next() {
+		var currentPost domain.Post
+		var repliesCount int32
+		if err = post.Scan(rows, &currentPost, &repliesCount); err != nil {
+			return nil, nil, err
+		}
+		post.SetRepliesCount(&currentPost, repliesCount)
+		resPosts = append(resPosts, currentPost)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+	if err = post.LoadQuoted(p.DB, post.Refs(resPosts)); err != nil {
+		return nil, nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(resPosts)-1; i < j; i, j = i+1, j-1 {
+			resPosts[i], resPosts[j] = resPosts[j], resPosts[i]
+		}
+	}
+	page := &utilities.Page{}
+	if len(resPosts) != 0 {
+		page = utilities.NewPage(repliesCursorScope, desc, cursor, len(resPosts), params.Limit,
+			[]string{strconv.FormatInt(resPosts[0].ID, 10)}, []string{strconv.FormatInt(resPosts[len(resPosts)-1].ID, 10)})
+	}
+	return resPosts, page, nil
+}
+
+const (
+	mentionsCursorScope = "user_mentions"
+	quotedByCursorScope = "post_quoted_by"
+)
+
+// GetUserMentions pages over posts mentioning the user from the newest one
+func (p *postUsecase) GetUserMentions(nickname string, params utilities.ArrayOutParams) (domain.PostArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, mentionsCursorScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	exists, err := p.UUCase.UserExists(nickname, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, user.NotExistsError
+	}
+
+	req := psql.Select(post.Columns).
+		From("mentions m").
+		Join("posts p on p.id = m.post").
+		Where(sq.Eq{"m.username": nickname})
+	return p.pageNewestPosts(req, "m.post", mentionsCursorScope, cursor, params)
+}
+
+// GetQuotedBy pages over posts quoting the post from the newest one, they may be in any thread and forum
+func (p *postUsecase) GetQuotedBy(id int64, params utilities.ArrayOutParams) (domain.PostArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, quotedByCursorScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = p.GetPostById(id); err != nil {
+		return nil, nil, err
+	}
+
+	req := psql.Select(post.Columns).
+		From("quotes q").
+		Join("posts p on p.id = q.post").
+		Where(sq.Eq{"q.quoted": id})
+	return p.pageNewestPosts(req, "q.post", quotedByCursorScope, cursor, params)
+}
+
+// pageNewestPosts pages over posts the request selects from the newest one, key is the post id column pages go by
+func (p *postUsecase) pageNewestPosts(req sq.SelectBuilder, key string, scope string, cursor *utilities.Cursor, params utilities.ArrayOutParams) (domain.PostArray, *utilities.Page, error) {
+	since, desc, backward := params.Since, true, false
+	if cursor != nil {
+		since, desc, backward = cursor.Key[0], cursor.Desc, cursor.Backward
+	}
+	if since != "" {
+		sinceID, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		if desc != backward {
+			req = req.Where(sq.Lt{key: sinceID})
+		} else {
+			req = req.Where(sq.Gt{key: sinceID})
+		}
+	}
+	if desc != backward {
+		req = req.OrderBy(key + " desc")
+	} else {
+		req = req.OrderBy(key + " asc")
+	}
+	query, args, err := req.Limit(uint64(params.Limit)).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := p.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	resPosts := make(domain.PostArray, 0)
+	for rows.Next() {
+		var currentPost domain.Post
+		if err = post.Scan(rows, &currentPost); err != nil {
+			return nil, nil, err
+		}
+		resPosts = append(resPosts, currentPost)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+	if err = post.LoadQuoted(p.DB, post.Refs(resPosts)); err != nil {
+		return nil, nil, err
+	}
+
+	if backward {
+		for i, j := 0, len(resPosts)-1; i < j; i, j = i+1, j-1 {
+			resPosts[i], resPosts[j] = resPosts[j], resPosts[i]
+		}
+	}
+	page := &utilities.Page{}
+	if len(resPosts) != 0 {
+		page = utilities.NewPage(scope, desc, cursor, len(resPosts), params.Limit,
+			[]string{strconv.FormatInt(resPosts[0].ID, 10)}, []string{strconv.FormatInt(resPosts[len(resPosts)-1].ID, 10)})
+	}
+	return resPosts, page, nil
+}
+
+// AttachHTML sets rendered messages of the posts for the clients asking for them
+func (p *postUsecase) AttachHTML(posts []*domain.Post) error {
+	return post.AttachHTML(p.DB, posts)
+}
+
+// GetPostContext returns the ancestors of the post from the root of its tree followed by the post itself
+func (p *postUsecase) GetPostContext(id int64) (domain.PostArray, error) {
+	query := "select " + post.Columns + " from posts p where p.id = any((select way[2:] from posts where id = $1)) order by p.way;"
+	rows, err := p.DB.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resPosts := make(domain.PostArray, 0)
+	for rows.Next() {
+		var currentPost domain.Post
+		if err = post.Scan(rows, &currentPost); err != nil {
+			return nil, err
+		}
+		resPosts = append(resPosts, currentPost)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	if len(resPosts) == 0 {
+		return nil, post.NotFoundError
+	}
+	if err = post.LoadQuoted(p.DB, post.Refs(resPosts)); err != nil {
+		return nil, err
+	}
+	return resPosts, nil
+}
+
+const (
+	lookupPostsQuery   = "select " + post.Columns + " from posts p where p.id = any($1);"
+	lookupUsersQuery   = "select " + user.Columns + " from users where nickname in (select author from posts where id = any($1));"
+	lookupThreadsQuery = "select " + thread.Columns + " from threads where id in (select thread from posts where id = any($1));"
+	lookupForumsQuery  = "select " + forum.Columns + " from forums where slug in (select forum from posts where id = any($1));"
+)
+
+// LookupPosts fetches the posts with their related entities in one round trip and what they quote in another.
+// Both run in one read only transaction on a snapshot, so related entities and quotes match the posts.
+// Posts come in the order of the ids, ids of posts that do not exist are reported as missing.
+func (p *postUsecase) LookupPosts(ids []int64, relatedUser bool, relatedForum bool, relatedThread bool) (*domain.PostLookupResult, error) {
+	tx, err := p.DB.BeginEx(context.Background(), &pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	batch := tx.BeginBatch()
+	defer batch.Close()
+
+	// batched queries are not prepared, so parameter types are given and every column comes in text format
+	args, oids := []interface{}{ids}, []pgtype.OID{pgtype.Int8ArrayOID}
+	batch.Queue(lookupPostsQuery, args, oids, nil)
+	if relatedUser {
+		batch.Queue(lookupUsersQuery, args, oids, nil)
+	}
+	if relatedThread {
+		batch.Queue(lookupThreadsQuery, args, oids, nil)
+	}
+	if relatedForum {
+		batch.Queue(lookupForumsQuery, args, oids, nil)
+	}
+	if err = batch.Send(context.Background(), nil); err != nil {
+		return nil, err
+	}
+
+	res := &domain.PostLookupResult{Posts: make(domain.PostArray, 0, len(ids))}
+	rows, err := batch.QueryResults()
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]domain.Post, len(ids))
+	for rows.Next() {
+		var currentPost domain.Post
+		if err = post.Scan(rows, &currentPost); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		found[currentPost.ID] = currentPost
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if foundPost, ok := found[id]; ok {
+			res.Posts = append(res.Posts, foundPost)
+		} else {
+			res.Missing = append(res.Missing, id)
+		}
+	}
+
+	if relatedUser {
+		if rows, err = batch.QueryResults(); err != nil {
+			return nil, err
+		}
+		res.Users = make(domain.UserArray, 0)
+		for rows.Next() {
+			var currentUser domain.User
+			if err = user.Scan(rows, &currentUser); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			res.Users = append(res.Users, currentUser)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, rows.Err()
+		}
+	}
+	if relatedThread {
+		if rows, err = batch.QueryResults(); err != nil {
+			return nil, err
+		}
+		if res.Threads, err = thread.ScanRows(rows); err != nil {
+			return nil, err
+		}
+	}
+	if relatedForum {
+		if rows, err = batch.QueryResults(); err != nil {
+			return nil, err
+		}
+		res.Forums = make(domain.ForumArray, 0)
+		for rows.Next() {
+			var currentForum domain.Forum
+			if err = forum.Scan(rows, &currentForum); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			res.Forums = append(res.Forums, currentForum)
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return nil, rows.Err()
+		}
+	}
+	// the connection is busy until the batch is closed
+	if err = batch.Close(); err != nil {
+		return nil, err
+	}
+	if err = post.LoadQuoted(tx, post.Refs(res.Posts)); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (p *postUsecase) UpdatePostDetails(id int64, postUpdate domain.Post) (*domain.Post, error) {
 	foundPost, err := p.GetPostById(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if postUpdate.Version != 0 && postUpdate.Version != foundPost.Version {
+		return nil, utilities.PreconditionFailed
+	}
 	if postUpdate.Message == "" || postUpdate.Message == foundPost.Message {
 		return foundPost, nil
 	}
 
-	query := "update posts set message = $1, is_edited = true where id = $2;"
-	_, err = p.DB.Exec(query, postUpdate.Message, id)
+	tx, err := p.DB.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	edited := domain.PostArray{{ID: id, Message: postUpdate.Message}}
+	if err = post.ResolveMentions(tx, edited); err != nil {
+		return nil, err
+	}
+	messageHTML := post.RenderMessage(postUpdate.Message)
+	query := "update posts set message = $1, message_html = $5, is_edited = true, mentions = $4 where id = $2 and ($3 = 0 or version = $3) returning version;"
+	err = tx.QueryRow(query, postUpdate.Message, id, postUpdate.Version, edited[0].Mentions, messageHTML).Scan(&foundPost.Version)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, utilities.PreconditionFailed
+		}
+		return nil, err
+	}
+	// mentions follow the edited message, users no longer mentioned lose the post from their mentions
+	if _, err = tx.Exec("delete from mentions where post = $1;", id); err != nil {
+		return nil, err
+	}
+	if err = post.SaveMentions(tx, edited); err != nil {
+		return nil, err
+	}
 	foundPost.Message = postUpdate.Message
 	foundPost.IsEdited = true
+	foundPost.Mentions = edited[0].Mentions
+	if err = webhook.Enqueue(tx, webhook.PostEdited, foundPost); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
 	return foundPost, nil
 }
+
+// Vote statements return the post with the change of the vote, the post itself is read from the statement snapshot,
+// which does not see what vote triggers do
+const (
+	castPostVoteQuery = `with prev as (select voice from post_votes where post = $1 and username = $2),
+     cast_vote as (insert into post_votes(post, username, voice) values ($1, $2, $3)
+         on conflict (post, username) do update set voice = excluded.voice where post_votes.voice <> excluded.voice
+         returning voice)
+select ` + post.Columns + `, coalesce((select voice from cast_vote), (select voice from prev), 0) - coalesce((select voice from prev), 0), true
+from posts p where p.id = $1;`
+	retractPostVoteQuery = `with retracted as (delete from post_votes where post = $1 and username = $2 returning voice)
+select ` + post.Columns + `, -coalesce((select voice from retracted), 0), exists(select 1 from users where nickname = $2)
+from posts p where p.id = $1;`
+)
+
+// CreatePostVote upserts the vote of the user for the post, voice 0 retracts it.
+// Post vote triggers keep posts.votes and the author karma in sync with the votes.
+func (p *postUsecase) CreatePostVote(id int64, vote domain.Vote) (*domain.Post, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	resPost := &domain.Post{}
+	var change, voice int32
+	var userExists bool
+	if vote.Voice != nil {
+		voice = *vote.Voice
+	}
+	if voice == 0 {
+		err = post.Scan(tx.QueryRow(retractPostVoteQuery, id, vote.Nickname), resPost, &change, &userExists)
+	} else {
+		err = post.Scan(tx.QueryRow(castPostVoteQuery, id, vote.Nickname, voice), resPost, &change, &userExists)
+	}
+	if err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "post_votes_username_fkey" {
+				return nil, post.VoterNotExists
+			}
+			return nil, post.NotFoundError
+		}
+		if err == pgx.ErrNoRows {
+			return nil, post.NotFoundError
+		}
+		return nil, err
+	}
+	if !userExists {
+		return nil, post.VoterNotExists
+	}
+	resPost.Votes += change
+	voteCast := domain.VoteCast{Nickname: vote.Nickname, Voice: voice, Post: id, Votes: resPost.Votes}
+	if err = webhook.Enqueue(tx, webhook.VoteCast, voteCast); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	p.Cache.Invalidate(cache.UserKey(resPost.Author))
+	return resPost, nil
+}
+
+// SplitPost moves the post and its whole subtree into a new thread of the same forum.
+// Empty fields of the new thread are taken from the split post and its old thread.
+func (p *postUsecase) SplitPost(id int64, t domain.Thread) (*domain.Thread, error) {
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var rootWay []int64
+	var oldThread int32
+	var rootAuthor, rootMessage, forumSlug string
+	query := "select way, thread, author, message, forum from posts where id = $1 for update;"
+	err = tx.QueryRow(query, id).Scan(&rootWay, &oldThread, &rootAuthor, &rootMessage, &forumSlug)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, post.NotFoundError
+		}
+		return nil, err
+	}
+
+	var oldTitle string
+	query = "select title from threads where id = $1 for update;"
+	if err = tx.QueryRow(query, oldThread).Scan(&oldTitle); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, thread.NotFound
+		}
+		return nil, err
+	}
+
+	if t.Author == "" {
+		t.Author = rootAuthor
+	}
+	if t.Message == "" {
+		t.Message = rootMessage
+	}
+	if t.Title == "" {
+		t.Title = oldTitle
+	}
+	var slug interface{}
+	if t.Slug != "" {
+		slug = t.Slug
+	}
+
+	newThread := &domain.Thread{}
+	var createdSlug *string
+	query = "insert into threads(author, forum, message, title, slug) values ($1, $2, $3, $4, $5) returning id, author, forum, message, title, created, slug;"
+	err = tx.QueryRow(query, t.Author, forumSlug, t.Message, t.Title, slug).
+		Scan(&newThread.ID, &newThread.Author, &newThread.Forum, &newThread.Message, &newThread.Title, &newThread.Created, &createdSlug)
+	if err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok {
+			if pgErr.Code == "23505" {
+				return nil, thread.AlreadyExists
+			}
+			if pgErr.Code == "23503" {
+				return nil, thread.AuthorNotExists
+			}
+		}
+		return nil, err
+	}
+	if createdSlug != nil {
+		newThread.Slug = *createdSlug
+	}
+
+	// way of the split post becomes {0, id}, its descendants keep the path below it
+	query = `update posts
+			set thread = $1,
+				parent = case when id = $2 then 0 else parent end,
+				way = array [0::bigint] || way[$3:]
+			where thread = $4 and way[1:$3] = $5;`
+	_, err = tx.Exec(query, newThread.ID, id, len(rootWay), oldThread, rootWay)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = tx.Exec(thread.RefreshActivityQuery, newThread.ID, oldThread); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(thread.RefreshForumActivityQuery, forumSlug, forumSlug); err != nil {
+		return nil, err
+	}
+	// authors of the moved posts posted in the new thread, so they are subscribed to it
+	query = "insert into thread_subscriptions(thread, username) select distinct $1::bigint, author from posts where thread = $1 on conflict do nothing;"
+	if _, err = tx.Exec(query, newThread.ID); err != nil {
+		return nil, err
+	}
+	// moved posts keep their ids, so readers of the old thread have read the same part of the new one
+	query = "insert into read_markers(thread, username, last_read) select $1, username, last_read from read_markers where thread = $2;"
+	if _, err = tx.Exec(query, newThread.ID, oldThread); err != nil {
+		return nil, err
+	}
+	if err = webhook.Enqueue(tx, webhook.ThreadCreated, newThread); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	p.Cache.Invalidate(cache.ForumKey(forumSlug))
+	return newThread, nil
+}
@@ -0,0 +1,86 @@
+package delivery
+
+import (
+	"github.com/fasthttp/router"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/search"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
+)
+
+type searchHandler struct {
+	searchUsecase domain.SearchUsecase
+	postUsecase   domain.PostUsecase
+}
+
+func NewSearchHandler(r *router.Router, su domain.SearchUsecase, pu domain.PostUsecase) {
+	h := searchHandler{
+		searchUsecase: su,
+		postUsecase:   pu,
+	}
+
+	r.GET("/api/search", h.searchHandler)
+}
+
+func (handler *searchHandler) searchHandler(ctx *fasthttp.RequestCtx) {
+	queryArgs := ctx.QueryArgs()
+	params, err := utilities.NewArrayOutParams(queryArgs)
+	if err != nil {
+		log.WithError(err).Error(errors.QuerystringParseError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONQuerystringErrorMessage)
+		return
+	}
+	if !queryArgs.Has("limit") {
+		params.Limit = 20
+	}
+	if fieldErrors := validation.Params(*params, validation.SinceDate); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	q := domain.SearchQuery{
+		Query:  string(queryArgs.Peek("q")),
+		Type:   string(queryArgs.Peek("type")),
+		Lang:   string(queryArgs.Peek("lang")),
+		Forum:  string(queryArgs.Peek("forum")),
+		Author: string(queryArgs.Peek("author")),
+		Since:  params.Since,
+		Until:  string(queryArgs.Peek("until")),
+		Limit:  params.Limit,
+		Cursor: string(queryArgs.Peek("cursor")),
+	}
+	if queryArgs.Has("thread") {
+		threadID, err := queryArgs.GetUint("thread")
+		if err != nil {
+			log.WithError(err).Error(errors.QuerystringParseError)
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONQuerystringErrorMessage)
+			return
+		}
+		q.Thread = int32(threadID)
+	}
+
+	results, err := handler.searchUsecase.Search(q)
+	if err != nil {
+		log.WithError(err).Error("search error")
+		utilities.Resp(ctx, search.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		posts := make([]*domain.Post, 0, len(results.Results))
+		for _, result := range results.Results {
+			if result.Post != nil {
+				posts = append(posts, result.Post)
+			}
+		}
+		if err = handler.postUsecase.AttachHTML(posts); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.SetPageLinks(ctx, &utilities.Page{Next: results.Next, Prev: results.Prev})
+	utilities.Resp(ctx, fasthttp.StatusOK, results)
+}
@@ -0,0 +1,25 @@
+package search
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/utilities"
+)
+
+var (
+	EmptyQuery    = errors.New("search query is empty")
+	WrongType     = errors.New("wrong search type")
+	WrongLanguage = errors.New("search language must be one of: " + strings.Join(strings.Fields(constants.SearchLanguages), ", "))
+	WrongDate     = errors.New("wrong search date")
+)
+
+func CodeFromError(err error) int {
+	switch err {
+	case EmptyQuery, WrongType, WrongLanguage, WrongDate, utilities.CursorError:
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx"
+	"html"
+	"strconv"
+	"strings"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/search"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+const (
+	// snippet bounds are control characters so that the message itself can be html escaped
+	startSel         = "\x01"
+	stopSel          = "\x02"
+	headlineOptions  = "StartSel=\x01, StopSel=\x02, MaxFragments=2, MinWords=10, MaxWords=30"
+	postResultType   = "post"
+	threadResultType = "thread"
+)
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+type searchUsecase struct {
+	DB *pgx.ConnPool
+}
+
+func NewSearchUsecase(db *pgx.ConnPool) domain.SearchUsecase {
+	return &searchUsecase{
+		DB: db,
+	}
+}
+
+func (s *searchUsecase) Search(q domain.SearchQuery) (*domain.SearchResults, error) {
+	q.Query = strings.TrimSpace(q.Query)
+	if q.Query == "" {
+		return nil, search.EmptyQuery
+	}
+	if q.Type == "" {
+		q.Type = postResultType
+	}
+	if q.Type != postResultType && q.Type != threadResultType {
+		return nil, search.WrongType
+	}
+	if q.Lang == "" {
+		q.Lang = constants.SearchLanguage
+	} else if !indexedLanguage(q.Lang) {
+		return nil, search.WrongLanguage
+	}
+	for _, date := range []string{q.Since, q.Until} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(constants.TimeLayout, date); err != nil {
+			return nil, search.WrongDate
+		}
+	}
+
+	var cursor *utilities.Cursor
+	if q.Cursor != "" {
+		var err error
+		cursor, err = utilities.DecodeCursor(q.Cursor, cursorScope(q), 2)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// results go by rank desc and id desc, a backward page inverts both
+	order := "desc"
+	if cursor != nil && cursor.Backward {
+		order = "asc"
+	}
+
+	var req sq.SelectBuilder
+	var columns string
+	if q.Type == postResultType {
+		req = postsSearchRequest(q)
+		columns = "r.id, r.parent, r.author, r.message, r.is_edited, r.forum, r.thread, r.created, r.votes, r.mentions, r.quotes, r.version, r.rank"
+	} else {
+		req = threadsSearchRequest(q)
+		columns = "r.id, r.title, r.author, r.forum, r.message, r.slug, r.created, r.votes, r.tags, r.rank"
+	}
+	if cursor != nil && cursor.Backward {
+		req = req.Where("(r.rank, r.id) > (?::real, ?)", cursor.Key[0], cursor.Key[1])
+	} else if cursor != nil {
+		req = req.Where("(r.rank, r.id) < (?::real, ?)", cursor.Key[0], cursor.Key[1])
+	}
+	req = req.OrderBy("r.rank "+order, "r.id "+order).Limit(uint64(q.Limit))
+
+	// headlines are the most expensive part, so they are built for the found page only
+	query, args, err := psql.Select(columns).
+		Column(sq.Expr("ts_headline(?::regconfig, r.document, r.q, ?)", q.Lang, headlineOptions)).
+		FromSelect(req, "r").
+		OrderBy("r.rank "+order, "r.id "+order).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &domain.SearchResults{Results: make([]domain.SearchResult, 0)}
+	ids := make([]int64, 0)
+	posts := make([]*domain.Post, 0)
+	for rows.Next() {
+		var current domain.SearchResult
+		var snippet string
+		current.Type = q.Type
+		if q.Type == postResultType {
+			p := &domain.Post{}
+			err = post.Scan(rows, p, &current.Rank, &snippet)
+			current.Post = p
+			ids = append(ids, p.ID)
+			posts = append(posts, p)
+		} else {
+			t := &domain.Thread{}
+			var slug *string
+			err = rows.Scan(&t.ID, &t.Title, &t.Author, &t.Forum, &t.Message, &slug, &t.Created, &t.Votes, &t.Tags,
+				&current.Rank, &snippet)
+			if slug != nil {
+				t.Slug = *slug
+			}
+			current.Thread = t
+			ids = append(ids, int64(t.ID))
+		}
+		if err != nil {
+			return nil, err
+		}
+		current.Snippet = highlight(snippet)
+		res.Results = append(res.Results, current)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	if err = post.LoadQuoted(s.DB, posts); err != nil {
+		return nil, err
+	}
+
+	if cursor != nil && cursor.Backward {
+		for i, j := 0, len(res.Results)-1; i < j; i, j = i+1, j-1 {
+			res.Results[i], res.Results[j] = res.Results[j], res.Results[i]
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+	if len(res.Results) != 0 {
+		last := len(res.Results) - 1
+		page := utilities.NewPage(cursorScope(q), false, cursor, len(res.Results), q.Limit,
+			cursorKey(res.Results[0].Rank, ids[0]), cursorKey(res.Results[last].Rank, ids[last]))
+		res.Next, res.Prev = page.Next, page.Prev
+	}
+	return res, nil
+}
+
+// indexedLanguage tells if the documents are indexed for the configuration, searching others would scan every row
+func indexedLanguage(lang string) bool {
+	for _, indexed := range strings.Fields(constants.SearchLanguages) {
+		if lang == indexed {
+			return true
+		}
+	}
+	return false
+}
+
+// documentVector returns the stored column for the default language and the indexed expression for the others.
+// The language is inlined rather than bound, so the planner matches the expression indexes, it is one of the indexed ones.
+func documentVector(lang string, column string, indexed string) string {
+	if lang == constants.SearchLanguage {
+		return indexed
+	}
+	return "to_tsvector('" + lang + "', " + column + ")"
+}
+
+func postsSearchRequest(q domain.SearchQuery) sq.SelectBuilder {
+	vector := documentVector(q.Lang, "p.message", "p.message_tsv")
+
+	req := psql.Select(post.Columns, "p.message as document").
+		Column("ts_rank("+vector+", q) as rank").
+		Column("q").
+		From("posts p").
+		JoinClause("cross join websearch_to_tsquery(?::regconfig, ?) q", q.Lang, q.Query).
+		Where(vector + " @@ q")
+	if q.Forum != "" {
+		req = req.Where(sq.Eq{"p.forum": q.Forum})
+	}
+	if q.Thread != 0 {
+		req = req.Where(sq.Eq{"p.thread": q.Thread})
+	}
+	if q.Author != "" {
+		req = req.Where(sq.Eq{"p.author": q.Author})
+	}
+	if q.Since != "" {
+		req = req.Where("p.created >= ?::timestamptz", q.Since)
+	}
+	if q.Until != "" {
+		req = req.Where("p.created <= ?::timestamptz", q.Until)
+	}
+	return psql.Select("*").FromSelect(req, "r")
+}
+
+func threadsSearchRequest(q domain.SearchQuery) sq.SelectBuilder {
+	title := documentVector(q.Lang, "t.title", "t.title_tsv")
+	message := documentVector(q.Lang, "t.message", "t.message_tsv")
+
+	req := psql.Select("t.id, t.title, t.author, t.forum, t.message, t.slug, t.created, t.votes, t.tags", "t.title || ' ' || t.message as document").
+		Column("ts_rank(setweight("+title+", 'A') || setweight("+message+", 'B'), q) as rank").
+		Column("q").
+		From("threads t").
+		JoinClause("cross join websearch_to_tsquery(?::regconfig, ?) q", q.Lang, q.Query).
+		Where("(" + title + " @@ q or " + message + " @@ q)")
+	if q.Forum != "" {
+		req = req.Where(sq.Eq{"t.forum": q.Forum})
+	}
+	if q.Thread != 0 {
+		req = req.Where(sq.Eq{"t.id": q.Thread})
+	}
+	if q.Author != "" {
+		req = req.Where(sq.Eq{"t.author": q.Author})
+	}
+	if q.Since != "" {
+		req = req.Where("t.created >= ?::timestamptz", q.Since)
+	}
+	if q.Until != "" {
+		req = req.Where("t.created <= ?::timestamptz", q.Until)
+	}
+	return psql.Select("*").FromSelect(req, "r")
+}
+
+func highlight(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, startSel, "<mark>")
+	return strings.ReplaceAll(snippet, stopSel, "</mark>")
+}
+
+// cursorScope binds cursors to the query and its filters, the hash keeps long queries out of the cursor
+func cursorScope(q domain.SearchQuery) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{q.Query, q.Lang, q.Forum, strconv.Itoa(int(q.Thread)), q.Author, q.Since, q.Until}, "\x00")))
+	return "search:" + q.Type + ":" + base64.RawURLEncoding.EncodeToString(hash[:16])
+}
+
+func cursorKey(rank float32, id int64) []string {
+	return []string{strconv.FormatFloat(float64(rank), 'g', -1, 32), strconv.FormatInt(id, 10)}
+}
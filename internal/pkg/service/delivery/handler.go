@@ -22,6 +22,7 @@ func NewServiceHandler(r *router.Router, su domain.ServiceUsecase) {
 
 	s.POST("/clear", h.serviceClearHandler)
 	s.GET("/status", h.serviceStatusHandler)
+	s.GET("/cache", h.serviceCacheHandler)
 }
 
 func (handler *serviceHandler) serviceClearHandler(ctx *fasthttp.RequestCtx) {
@@ -43,3 +44,7 @@ func (handler *serviceHandler) serviceStatusHandler(ctx *fasthttp.RequestCtx) {
 	}
 	utilities.Resp(ctx, fasthttp.StatusOK, status)
 }
+
+func (handler *serviceHandler) serviceCacheHandler(ctx *fasthttp.RequestCtx) {
+	utilities.Resp(ctx, fasthttp.StatusOK, handler.serviceUsecase.CacheStats())
+}
@@ -2,19 +2,22 @@ package usecase
 
 import (
 	"github.com/jackc/pgx"
+	"technopark-dbms/internal/pkg/cache"
 	"technopark-dbms/internal/pkg/domain"
 )
 
 type serviceUsecase struct {
-	DB *pgx.ConnPool
+	DB    *pgx.ConnPool
+	Cache *cache.Cache
 }
 
 func (s *serviceUsecase) Clear() error {
-	query := "truncate forums, users, f_u, posts, threads, votes;"
+	query := "truncate forums, users, f_u, posts, threads, votes, post_votes, idempotency_keys, webhooks, outbox, webhook_deliveries, notifications, thread_subscriptions, forum_subscriptions, feed_visits, read_markers, mentions, quotes;"
 	_, err := s.DB.Exec(query)
 	if err != nil {
 		return err
 	}
+	s.Cache.Flush()
 	return nil
 }
 
@@ -28,8 +31,13 @@ func (s *serviceUsecase) Status() (*domain.Service, error) {
 	return res, nil
 }
 
-func NewServiceUsecase(db *pgx.ConnPool) domain.ServiceUsecase {
+func (s *serviceUsecase) CacheStats() *domain.CacheStats {
+	return s.Cache.Stats()
+}
+
+func NewServiceUsecase(db *pgx.ConnPool, c *cache.Cache) domain.ServiceUsecase {
 	return &serviceUsecase{
-		DB: db,
+		DB:    db,
+		Cache: c,
 	}
 }
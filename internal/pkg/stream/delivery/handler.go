@@ -0,0 +1,137 @@
+package delivery
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/fasthttp/router"
+	"github.com/mailru/easyjson"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/forum"
+	"technopark-dbms/internal/pkg/stream"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+// LastEventIDHeader is sent by reconnecting EventSource clients, it holds the id of the last post they got
+const LastEventIDHeader = "Last-Event-ID"
+
+type streamHandler struct {
+	streamUsecase domain.StreamUsecase
+	postUsecase   domain.PostUsecase
+}
+
+func NewStreamHandler(r *router.Router, su domain.StreamUsecase, pu domain.PostUsecase) {
+	h := streamHandler{
+		streamUsecase: su,
+		postUsecase:   pu,
+	}
+
+	r.GET("/api/thread/{slug_or_id}/stream", h.threadStreamHandler)
+	r.GET("/api/forum/{slug}/stream", h.forumStreamHandler)
+}
+
+func (handler *streamHandler) threadStreamHandler(ctx *fasthttp.RequestCtx) {
+	sinceID, ok := resumePoint(ctx)
+	if !ok {
+		return
+	}
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	subscription, err := handler.streamUsecase.SubscribeThread(slugOrId, sinceID)
+	if err != nil {
+		log.WithError(err).Error("thread stream subscribe error")
+		if err == thread.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	handler.streamEvents(ctx, subscription, utilities.RenderHTML(ctx))
+}
+
+func (handler *streamHandler) forumStreamHandler(ctx *fasthttp.RequestCtx) {
+	sinceID, ok := resumePoint(ctx)
+	if !ok {
+		return
+	}
+	slug := ctx.UserValue("slug").(string)
+	subscription, err := handler.streamUsecase.SubscribeForum(slug, sinceID)
+	if err != nil {
+		log.WithError(err).Error("forum stream subscribe error")
+		if err == forum.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	handler.streamEvents(ctx, subscription, utilities.RenderHTML(ctx))
+}
+
+// resumePoint takes the last seen post id from Last-Event-ID of a reconnect or from the since param,
+// it answers 400 itself when the id is malformed
+func resumePoint(ctx *fasthttp.RequestCtx) (int64, bool) {
+	field, value := LastEventIDHeader, string(ctx.Request.Header.Peek(LastEventIDHeader))
+	if value == "" {
+		field, value = "since", string(ctx.QueryArgs().Peek("since"))
+	}
+	if value == "" {
+		return 0, true
+	}
+	sinceID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sinceID < 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(domain.FieldErrorArray{
+			{Field: field, Message: "must be a post id"}}))
+		return 0, false
+	}
+	return sinceID, true
+}
+
+// streamEvents writes events as Server-Sent Events until the client goes away or the subscriber is dropped.
+// Created posts carry their id as the event id, so a reconnecting client resumes after the last one it got.
+func (handler *streamHandler) streamEvents(ctx *fasthttp.RequestCtx, subscription *domain.StreamSubscription, renderHTML bool) {
+	ctx.SetContentType("text/event-stream")
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-cache")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Cancel()
+		keepAlive := time.NewTicker(constants.StreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				if renderHTML && event.Post != nil {
+					// posts of an event are shared by all subscribers, so the rendered message goes to a copy
+					rendered := *event.Post
+					if err := handler.postUsecase.AttachHTML([]*domain.Post{&rendered}); err != nil {
+						log.WithError(err).Error("stream post render error")
+					}
+					event.Post = &rendered
+				}
+				data, err := easyjson.Marshal(event)
+				if err != nil {
+					log.WithError(err).Error("stream event encode error")
+					continue
+				}
+				if event.Type == stream.PostCreated {
+					_, _ = fmt.Fprintf(w, "id: %d\n", event.Post.ID)
+				}
+				_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			case <-keepAlive.C:
+				_, _ = w.WriteString(": keep-alive\n\n")
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,12 @@
+package stream
+
+// Event types, post and thread ones are announced by database triggers
+const (
+	PostCreated   = "post.created"
+	PostEdited    = "post.edited"
+	PostVoted     = "post.voted"
+	ThreadUpdated = "thread.updated"
+	ThreadVoted   = "thread.voted"
+	// Reset tells a resuming subscriber that it missed too much to be replayed and has to reload
+	Reset = "stream.reset"
+)
@@ -0,0 +1,336 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx"
+	log "github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/stream"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+// notification is the payload of database triggers, it points at the changed row
+type notification struct {
+	Type   string `json:"type"`
+	ID     int64  `json:"id"`
+	Thread int32  `json:"thread"`
+	Forum  string `json:"forum"`
+}
+
+var errQueueFull = errors.New("stream notification queue is full")
+
+// subscriber gets events of a thread or of a whole forum.
+// Events stays nil until missed posts are replayed, events coming meanwhile wait in pending.
+type subscriber struct {
+	thread   int32
+	forum    string
+	since    int64
+	events   chan domain.StreamEvent
+	pending  []domain.StreamEvent
+	replayed map[int64]bool
+}
+
+func (s *subscriber) matches(n notification) bool {
+	if s.forum != "" {
+		return strings.EqualFold(s.forum, n.Forum)
+	}
+	return s.thread == n.Thread
+}
+
+type streamUsecase struct {
+	DB     *pgx.ConnPool
+	TUCase domain.ThreadUsecase
+	FUCase domain.ForumUsecase
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+
+	// queue holds notification payloads the listener got and the loader has not taken yet
+	queueMu sync.Mutex
+	queue   []string
+	wake    chan struct{}
+}
+
+// NewStreamUsecase starts listening to the events every server instance gets from the database
+func NewStreamUsecase(db *pgx.ConnPool, threadUsecase domain.ThreadUsecase, forumUsecase domain.ForumUsecase) domain.StreamUsecase {
+	u := &streamUsecase{
+		DB:          db,
+		TUCase:      threadUsecase,
+		FUCase:      forumUsecase,
+		subscribers: make(map[*subscriber]struct{}),
+		wake:        make(chan struct{}, 1),
+	}
+	go u.listen()
+	go u.load()
+	return u
+}
+
+func (u *streamUsecase) SubscribeThread(s utilities.SlugOrId, sinceID int64) (*domain.StreamSubscription, error) {
+	threadInfo, err := u.TUCase.GetThreadIdAndForum(s)
+	if err != nil {
+		return nil, err
+	}
+	return u.subscribe(&subscriber{thread: threadInfo.ID, since: sinceID}, "p.thread = $1", threadInfo.ID)
+}
+
+func (u *streamUsecase) SubscribeForum(slug string, sinceID int64) (*domain.StreamSubscription, error) {
+	forumDetails, err := u.FUCase.GetForumDetails(slug)
+	if err != nil {
+		return nil, err
+	}
+	return u.subscribe(&subscriber{forum: forumDetails.Slug, since: sinceID}, "p.forum = $1", forumDetails.Slug)
+}
+
+// subscribe registers the subscriber before reading posts it missed, so no post falls between them.
+// Posts created after since are replayed first, live copies of them are skipped.
+// Posts resume by id, which is taken at insert and not at commit: a post that commits after a post
+// with a greater id was sent is missed by a subscriber reconnecting in between. Only posts created
+// concurrently in the thread or forum commit out of id order.
+func (u *streamUsecase) subscribe(s *subscriber, filter string, arg interface{}) (*domain.StreamSubscription, error) {
+	u.mu.Lock()
+	u.subscribers[s] = struct{}{}
+	u.mu.Unlock()
+	cancel := func() {
+		u.mu.Lock()
+		u.drop(s)
+		u.mu.Unlock()
+	}
+
+	missed := make([]domain.StreamEvent, 0)
+	if s.since != 0 {
+		query := "select " + post.Columns + " from posts p where " + filter + " and p.id > $2 order by p.id limit $3;"
+		rows, err := u.DB.Query(query, arg, s.since, constants.StreamBacklog+1)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		for rows.Next() {
+			missedPost := &domain.Post{}
+			if err = post.Scan(rows, missedPost); err != nil {
+				rows.Close()
+				cancel()
+				return nil, err
+			}
+			missed = append(missed, domain.StreamEvent{Type: stream.PostCreated, Post: missedPost})
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			cancel()
+			return nil, rows.Err()
+		}
+		if len(missed) > constants.StreamBacklog {
+			missed = []domain.StreamEvent{{Type: stream.Reset}}
+		}
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	events := make(chan domain.StreamEvent, len(missed)+len(s.pending)+constants.StreamBuffer)
+	s.replayed = make(map[int64]bool, len(missed))
+	for _, event := range missed {
+		events <- event
+		if event.Post != nil {
+			s.replayed[event.Post.ID] = true
+		}
+	}
+	for _, event := range s.pending {
+		if !s.isReplayed(event) {
+			events <- event
+		}
+	}
+	s.events, s.pending = events, nil
+	// the listener could have failed and dropped the subscriber while missed posts were read
+	if _, ok := u.subscribers[s]; !ok {
+		close(events)
+	}
+	return &domain.StreamSubscription{Events: events, Cancel: cancel}, nil
+}
+
+// isReplayed tells if the event is a post already sent to the subscriber as a missed one.
+// Ids of replayed posts are kept rather than the greatest one, a post committed out of id order still comes live.
+func (s *subscriber) isReplayed(event domain.StreamEvent) bool {
+	return event.Type == stream.PostCreated && s.replayed[event.Post.ID]
+}
+
+// drop must be called with the lock held
+func (u *streamUsecase) drop(s *subscriber) {
+	if _, ok := u.subscribers[s]; !ok {
+		return
+	}
+	delete(u.subscribers, s)
+	if s.events != nil {
+		close(s.events)
+	}
+}
+
+// listen keeps a connection listening to the events channel. Subscribers are dropped when it fails,
+// so that they resume from their last post instead of silently missing events until it is back.
+func (u *streamUsecase) listen() {
+	for {
+		err := u.listenConn()
+		log.WithError(err).Error("stream listen error")
+		u.queueMu.Lock()
+		u.queue = nil
+		u.queueMu.Unlock()
+		u.mu.Lock()
+		for s := range u.subscribers {
+			u.drop(s)
+		}
+		u.mu.Unlock()
+		time.Sleep(constants.StreamReconnectDelay)
+	}
+}
+
+// listenConn only queues notifications, loading them would hold the connection back
+// while a bulk insert announces a row after another
+func (u *streamUsecase) listenConn() error {
+	conn, err := u.DB.Acquire()
+	if err != nil {
+		return err
+	}
+	defer u.DB.Release(conn)
+
+	if err = conn.Listen(constants.StreamChannel); err != nil {
+		return err
+	}
+	for {
+		n, err := conn.WaitForNotification(context.Background())
+		if err != nil {
+			return err
+		}
+		u.queueMu.Lock()
+		if len(u.queue) >= constants.StreamQueueLimit {
+			u.queueMu.Unlock()
+			return errQueueFull
+		}
+		u.queue = append(u.queue, n.Payload)
+		u.queueMu.Unlock()
+		select {
+		case u.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// load takes all queued notifications at once, so a bulk insert costs a query instead of a query per row
+func (u *streamUsecase) load() {
+	for range u.wake {
+		u.queueMu.Lock()
+		payloads := u.queue
+		u.queue = nil
+		u.queueMu.Unlock()
+		if len(payloads) != 0 {
+			u.dispatch(payloads)
+		}
+	}
+}
+
+// dispatch loads the changed rows only if someone is subscribed to them and sends them to the subscribers
+// in the order of notifications. A subscriber that can't keep up is dropped, it resumes from its last post after reconnecting.
+func (u *streamUsecase) dispatch(payloads []string) {
+	type target struct {
+		n           notification
+		subscribers []*subscriber
+	}
+	targets := make([]target, 0)
+	u.mu.Lock()
+	for _, payload := range payloads {
+		n := notification{}
+		if err := json.Unmarshal([]byte(payload), &n); err != nil {
+			log.WithError(err).Error("stream notification decode error")
+			continue
+		}
+		t := target{n: n}
+		for s := range u.subscribers {
+			if s.matches(n) {
+				t.subscribers = append(t.subscribers, s)
+			}
+		}
+		if len(t.subscribers) != 0 {
+			targets = append(targets, t)
+		}
+	}
+	u.mu.Unlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	postIDs := make([]int64, 0)
+	threads := make(map[int32]*domain.Thread)
+	for _, t := range targets {
+		if strings.HasPrefix(t.n.Type, "thread.") {
+			threads[t.n.Thread] = nil
+		} else {
+			postIDs = append(postIDs, t.n.ID)
+		}
+	}
+	posts, err := u.loadPosts(postIDs)
+	if err != nil {
+		log.WithError(err).Error("stream event load error")
+		return
+	}
+	for id := range threads {
+		threadDetails, err := u.TUCase.GetThreadDetails(utilities.SlugOrId{ID: id})
+		if err != nil {
+			log.WithError(err).Error("stream event load error")
+			continue
+		}
+		threads[id] = threadDetails
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, t := range targets {
+		event := domain.StreamEvent{Type: t.n.Type}
+		if strings.HasPrefix(t.n.Type, "thread.") {
+			event.Thread = threads[t.n.Thread]
+		} else {
+			event.Post = posts[t.n.ID]
+		}
+		if event.Thread == nil && event.Post == nil {
+			continue
+		}
+		for _, s := range t.subscribers {
+			if _, ok := u.subscribers[s]; !ok || s.isReplayed(event) {
+				continue
+			}
+			if s.events == nil {
+				s.pending = append(s.pending, event)
+				continue
+			}
+			select {
+			case s.events <- event:
+			default:
+				u.drop(s)
+			}
+		}
+	}
+}
+
+// loadPosts reads the posts of a batch of notifications in one query
+func (u *streamUsecase) loadPosts(ids []int64) (map[int64]*domain.Post, error) {
+	posts := make(map[int64]*domain.Post, len(ids))
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	rows, err := u.DB.Query("select "+post.Columns+" from posts p where p.id = any($1);", ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		loaded := &domain.Post{}
+		if err = post.Scan(rows, loaded); err != nil {
+			return nil, err
+		}
+		posts[loaded.ID] = loaded
+	}
+	return posts, rows.Err()
+}
@@ -0,0 +1,194 @@
+package delivery
+
+import (
+	"github.com/fasthttp/router"
+	"github.com/mailru/easyjson"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/forum"
+	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
+)
+
+type subscriptionHandler struct {
+	subscriptionUsecase domain.SubscriptionUsecase
+	postUsecase         domain.PostUsecase
+}
+
+func NewSubscriptionHandler(r *router.Router, su domain.SubscriptionUsecase, pu domain.PostUsecase) {
+	h := subscriptionHandler{
+		subscriptionUsecase: su,
+		postUsecase:         pu,
+	}
+
+	r.POST("/api/thread/{slug_or_id}/subscribe", h.threadSubscribeHandler)
+	r.DELETE("/api/thread/{slug_or_id}/subscribe", h.threadUnsubscribeHandler)
+	r.POST("/api/forum/{slug}/subscribe", h.forumSubscribeHandler)
+	r.DELETE("/api/forum/{slug}/subscribe", h.forumUnsubscribeHandler)
+	r.GET("/api/user/{nickname}/subscriptions", h.userSubscriptionsHandler)
+	r.GET("/api/user/{nickname}/feed", h.userFeedHandler)
+	r.POST("/api/user/{nickname}/feed/read", h.userFeedReadHandler)
+}
+
+// subscriber reads the nickname from the body of a subscription, it answers 400 itself when it is invalid
+func subscriber(ctx *fasthttp.RequestCtx) (string, bool) {
+	parsedSubscriber := &domain.Subscriber{}
+	err := easyjson.Unmarshal(ctx.PostBody(), parsedSubscriber)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return "", false
+	}
+	if fieldErrors := validation.Struct(parsedSubscriber, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return "", false
+	}
+	return parsedSubscriber.Nickname, true
+}
+
+// unsubscriber takes the nickname from the query as unsubscriptions have no body
+func unsubscriber(ctx *fasthttp.RequestCtx) (string, bool) {
+	nickname := string(ctx.QueryArgs().Peek("nickname"))
+	if fieldErrors := validation.Var("nickname", nickname, "required,nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return "", false
+	}
+	return nickname, true
+}
+
+func (handler *subscriptionHandler) threadSubscribeHandler(ctx *fasthttp.RequestCtx) {
+	nickname, ok := subscriber(ctx)
+	if !ok {
+		return
+	}
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+
+	subscribedThread, err := handler.subscriptionUsecase.SubscribeThread(nickname, slugOrId)
+	handler.threadResp(ctx, subscribedThread, err)
+}
+
+func (handler *subscriptionHandler) threadUnsubscribeHandler(ctx *fasthttp.RequestCtx) {
+	nickname, ok := unsubscriber(ctx)
+	if !ok {
+		return
+	}
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+
+	unsubscribedThread, err := handler.subscriptionUsecase.UnsubscribeThread(nickname, slugOrId)
+	handler.threadResp(ctx, unsubscribedThread, err)
+}
+
+func (handler *subscriptionHandler) threadResp(ctx *fasthttp.RequestCtx, t *domain.Thread, err error) {
+	if err != nil {
+		log.WithError(err).Error("thread subscription error")
+		if err == thread.NotFound || err == user.NotExistsError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, t)
+}
+
+func (handler *subscriptionHandler) forumSubscribeHandler(ctx *fasthttp.RequestCtx) {
+	nickname, ok := subscriber(ctx)
+	if !ok {
+		return
+	}
+
+	subscribedForum, err := handler.subscriptionUsecase.SubscribeForum(nickname, ctx.UserValue("slug").(string))
+	handler.forumResp(ctx, subscribedForum, err)
+}
+
+func (handler *subscriptionHandler) forumUnsubscribeHandler(ctx *fasthttp.RequestCtx) {
+	nickname, ok := unsubscriber(ctx)
+	if !ok {
+		return
+	}
+
+	unsubscribedForum, err := handler.subscriptionUsecase.UnsubscribeForum(nickname, ctx.UserValue("slug").(string))
+	handler.forumResp(ctx, unsubscribedForum, err)
+}
+
+func (handler *subscriptionHandler) forumResp(ctx *fasthttp.RequestCtx, f *domain.Forum, err error) {
+	if err != nil {
+		log.WithError(err).Error("forum subscription error")
+		if err == forum.NotFound || err == user.NotExistsError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, f)
+}
+
+func (handler *subscriptionHandler) userSubscriptionsHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceAny)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	subscriptions, err := handler.subscriptionUsecase.GetSubscriptions(nickname, params.Limit)
+	if err != nil {
+		log.WithError(err).Error("user get subscriptions error")
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, subscriptions)
+}
+
+func (handler *subscriptionHandler) userFeedHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	feed, err := handler.subscriptionUsecase.GetFeed(nickname, *params)
+	if err != nil {
+		log.WithError(err).Error("user get feed error")
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(feed.Posts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, feed)
+}
+
+func (handler *subscriptionHandler) userFeedReadHandler(ctx *fasthttp.RequestCtx) {
+	nickname := ctx.UserValue("nickname").(string)
+	visit := &domain.FeedVisit{}
+	err := easyjson.Unmarshal(ctx.PostBody(), visit)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return
+	}
+	if fieldErrors := validation.Struct(visit, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	visit, err = handler.subscriptionUsecase.MarkFeedRead(nickname, visit.LastPost)
+	if err != nil {
+		log.WithError(err).Error("user feed mark read error")
+		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, visit)
+}
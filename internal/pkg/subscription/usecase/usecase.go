@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"github.com/jackc/pgx"
+	"strconv"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/forum"
+	"technopark-dbms/internal/pkg/post"
+	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
+	"technopark-dbms/internal/pkg/utilities"
+)
+
+const (
+	subscribeThreadQuery   = "insert into thread_subscriptions(thread, username) values ($1, $2) on conflict (username, thread) do update set active = true;"
+	unsubscribeThreadQuery = "insert into thread_subscriptions(thread, username, active) values ($1, $2, false) on conflict (username, thread) do update set active = false;"
+	subscribeForumQuery    = "insert into forum_subscriptions(forum, username) values ($1, $2) on conflict do nothing;"
+	unsubscribeForumQuery  = "delete from forum_subscriptions where forum = $1 and username = $2;"
+	subscribedForumsQuery  = "select " + forum.Columns + " from forums where slug in (select forum from forum_subscriptions where username = $1) order by slug;"
+	subscribedThreadsQuery = "select " + thread.Columns + " from threads where id in (select thread from thread_subscriptions where username = $1 and active) " +
+		"order by last_post_at desc, id desc limit $2;"
+	feedVisitQuery = "select last_post from feed_visits where username = $1;"
+	// posts of watched forums come unless the user left their thread, own posts are not news
+	feedQuery = "select " + post.Columns + " from posts p where p.id > $2 and p.author <> $1 and " +
+		"(p.thread in (select thread from thread_subscriptions where username = $1 and active) or " +
+		"p.forum in (select forum from forum_subscriptions where username = $1) and " +
+		"p.thread not in (select thread from thread_subscriptions where username = $1 and not active)) " +
+		"order by p.id limit $3;"
+	saveFeedVisitQuery = "insert into feed_visits(username, last_post) values ($1, $2) " +
+		"on conflict (username) do update set last_post = greatest(feed_visits.last_post, excluded.last_post) returning last_post;"
+)
+
+type subscriptionUsecase struct {
+	DB     *pgx.ConnPool
+	UUCase domain.UserUsecase
+	TUCase domain.ThreadUsecase
+	FUCase domain.ForumUsecase
+}
+
+func NewSubscriptionUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase, threadUsecase domain.ThreadUsecase, forumUsecase domain.ForumUsecase) domain.SubscriptionUsecase {
+	return &subscriptionUsecase{
+		DB:     db,
+		UUCase: userUsecase,
+		TUCase: threadUsecase,
+		FUCase: forumUsecase,
+	}
+}
+
+func (u *subscriptionUsecase) SubscribeThread(nickname string, s utilities.SlugOrId) (*domain.Thread, error) {
+	return u.setThreadSubscription(subscribeThreadQuery, nickname, s)
+}
+
+// UnsubscribeThread keeps the user away from the thread, posting in it again does not subscribe them
+func (u *subscriptionUsecase) UnsubscribeThread(nickname string, s utilities.SlugOrId) (*domain.Thread, error) {
+	return u.setThreadSubscription(unsubscribeThreadQuery, nickname, s)
+}
+
+func (u *subscriptionUsecase) setThreadSubscription(query string, nickname string, s utilities.SlugOrId) (*domain.Thread, error) {
+	threadInfo, err := u.TUCase.GetThreadIdAndForum(s)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = u.DB.Exec(query, threadInfo.ID, nickname); err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "thread_subscriptions_username_fkey" {
+				return nil, user.NotExistsError
+			}
+			return nil, thread.NotFound
+		}
+		return nil, err
+	}
+	return u.TUCase.GetThreadDetails(utilities.SlugOrId{ID: threadInfo.ID})
+}
+
+func (u *subscriptionUsecase) SubscribeForum(nickname string, slug string) (*domain.Forum, error) {
+	forumDetails, err := u.FUCase.GetForumDetails(slug)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = u.DB.Exec(subscribeForumQuery, forumDetails.Slug, nickname); err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "forum_subscriptions_username_fkey" {
+				return nil, user.NotExistsError
+			}
+			return nil, forum.NotFound
+		}
+		return nil, err
+	}
+	return forumDetails, nil
+}
+
+func (u *subscriptionUsecase) UnsubscribeForum(nickname string, slug string) (*domain.Forum, error) {
+	forumDetails, err := u.FUCase.GetForumDetails(slug)
+	if err != nil {
+		return nil, err
+	}
+	if err = u.checkUser(nickname); err != nil {
+		return nil, err
+	}
+	if _, err = u.DB.Exec(unsubscribeForumQuery, forumDetails.Slug, nickname); err != nil {
+		return nil, err
+	}
+	return forumDetails, nil
+}
+
+// GetSubscriptions lists watched forums and the limit of subscribed threads with the latest activity
+func (u *subscriptionUsecase) GetSubscriptions(nickname string, limit int32) (*domain.Subscriptions, error) {
+	if err := u.checkUser(nickname); err != nil {
+		return nil, err
+	}
+
+	rows, err := u.DB.Query(subscribedForumsQuery, nickname)
+	if err != nil {
+		return nil, err
+	}
+	res := &domain.Subscriptions{Forums: make(domain.ForumArray, 0)}
+	for rows.Next() {
+		var f domain.Forum
+		if err = forum.Scan(rows, &f); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		res.Forums = append(res.Forums, f)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	rows, err = u.DB.Query(subscribedThreadsQuery, nickname, limit)
+	if err != nil {
+		return nil, err
+	}
+	if res.Threads, err = thread.ScanRows(rows); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetFeed returns posts created since the last visit of the user oldest first, reading them does not move the visit.
+// Since shows the feed from the given post id instead.
+func (u *subscriptionUsecase) GetFeed(nickname string, params utilities.ArrayOutParams) (*domain.Feed, error) {
+	if err := u.checkUser(nickname); err != nil {
+		return nil, err
+	}
+
+	var since int64
+	if params.Since != "" {
+		var err error
+		if since, err = strconv.ParseInt(params.Since, 10, 64); err != nil {
+			return nil, err
+		}
+	} else if err := u.DB.QueryRow(feedVisitQuery, nickname).Scan(&since); err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	rows, err := u.DB.Query(feedQuery, nickname, since, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	res := &domain.Feed{Posts: make(domain.PostArray, 0)}
+	for rows.Next() {
+		var currentPost domain.Post
+		if err = post.Scan(rows, &currentPost); err != nil {
+			return nil, err
+		}
+		res.Posts = append(res.Posts, currentPost)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	if err = post.LoadQuoted(u.DB, post.Refs(res.Posts)); err != nil {
+		return nil, err
+	}
+	res.HasMore = len(res.Posts) == int(params.Limit)
+	return res, nil
+}
+
+// MarkFeedRead moves the visit of the user to the last post the client has shown, it never goes back
+func (u *subscriptionUsecase) MarkFeedRead(nickname string, lastPost int64) (*domain.FeedVisit, error) {
+	if err := u.checkUser(nickname); err != nil {
+		return nil, err
+	}
+	res := &domain.FeedVisit{}
+	if err := u.DB.QueryRow(saveFeedVisitQuery, nickname, lastPost).Scan(&res.LastPost); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (u *subscriptionUsecase) checkUser(nickname string) error {
+	exists, err := u.UUCase.UserExists(nickname, "")
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return user.NotExistsError
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package thread
+
+// Activity is the posts count and the last post of a thread, forums keep the last post only.
+// CreatePosts updates it incrementally, while moving posts between threads
+// refreshes it from the posts the threads and forums are left with.
+// Thread without posts gets its creation time as the last activity from the hot score trigger.
+const (
+	// RefreshActivityQuery refreshes activity of threads $1 and $2
+	RefreshActivityQuery = `update threads
+		set posts = (select count(*) from posts where thread = threads.id),
+			(last_post_id, last_post_author, last_post_at) = (select id, author, created
+				from posts where thread = threads.id order by id desc limit 1)
+		where id in ($1, $2);`
+
+	// RefreshForumActivityQuery refreshes activity of forums $1 and $2
+	RefreshForumActivityQuery = `update forums
+		set (last_post_id, last_post_author, last_post_thread, last_post_at) = (select id, author, thread, created
+			from posts where forum = forums.slug order by id desc limit 1)
+		where slug in ($1, $2);`
+)
@@ -12,16 +12,20 @@ import (
 	"technopark-dbms/internal/pkg/errors"
 	"technopark-dbms/internal/pkg/post"
 	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
 )
 
 type threadHandler struct {
 	threadUsecase domain.ThreadUsecase
+	postUsecase   domain.PostUsecase
 }
 
-func NewThreadHandler(r *router.Router, tu domain.ThreadUsecase) {
+func NewThreadHandler(r *router.Router, tu domain.ThreadUsecase, pu domain.PostUsecase) {
 	h := threadHandler{
 		threadUsecase: tu,
+		postUsecase:   pu,
 	}
 	s := r.Group("/api/thread")
 
@@ -30,6 +34,13 @@ func NewThreadHandler(r *router.Router, tu domain.ThreadUsecase) {
 	s.POST("/{slug_or_id}/details", h.threadUpdateDetailsHandler)
 	s.GET("/{slug_or_id}/posts", h.threadGetPostsHandler)
 	s.POST("/{slug_or_id}/vote", h.threadVoteHandler)
+	s.DELETE("/{slug_or_id}/vote", h.threadRetractVoteHandler)
+	s.GET("/{slug_or_id}/votes", h.threadGetVotesHandler)
+	s.POST("/{slug_or_id}/merge", h.threadMergeHandler)
+	s.POST("/{slug_or_id}/read", h.threadMarkReadHandler)
+
+	r.GET("/api/tags/{tag}/threads", h.tagGetThreadsHandler)
+	r.GET("/api/threads/trending", h.trendingThreadsHandler)
 }
 
 func (handler *threadHandler) threadCreatePostsHandler(ctx *fasthttp.RequestCtx) {
@@ -43,6 +54,11 @@ func (handler *threadHandler) threadCreatePostsHandler(ctx *fasthttp.RequestCtx)
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedPosts, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
 	createdPosts, err := handler.threadUsecase.CreatePosts(slugOrId, parsedPosts)
 	responseStatus := fasthttp.StatusCreated
 	if err != nil {
@@ -53,7 +69,7 @@ func (handler *threadHandler) threadCreatePostsHandler(ctx *fasthttp.RequestCtx)
 		} else if err == post.InvalidParentError {
 			utilities.Resp(ctx, fasthttp.StatusConflict, errors.JSONErrorMessage(err))
 			return
-		} else if err == thread.AuthorNotExists {
+		} else if err == thread.AuthorNotExists || err == post.QuotedNotFound {
 			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
 			return
 		} else {
@@ -61,6 +77,13 @@ func (handler *threadHandler) threadCreatePostsHandler(ctx *fasthttp.RequestCtx)
 			return
 		}
 	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(createdPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
 	_ = json.NewEncoder(ctx).Encode(createdPosts)
 	ctx.SetContentType("application/json")
 	ctx.SetStatusCode(responseStatus)
@@ -68,6 +91,12 @@ func (handler *threadHandler) threadCreatePostsHandler(ctx *fasthttp.RequestCtx)
 
 func (handler *threadHandler) threadGetDetailsHandler(ctx *fasthttp.RequestCtx) {
 	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	viewer := utilities.Viewer(ctx)
+	utilities.VaryOnViewer(ctx)
+	if fieldErrors := validation.Var(utilities.ViewerHeader, viewer, "nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
 	threadDetails, err := handler.threadUsecase.GetThreadDetails(slugOrId)
 	if err != nil {
 		log.WithError(err).Error("thread get details error")
@@ -83,7 +112,15 @@ func (handler *threadHandler) threadGetDetailsHandler(ctx *fasthttp.RequestCtx)
 			return
 		}
 	}
-	utilities.Resp(ctx, fasthttp.StatusOK, threadDetails)
+	if viewer != "" {
+		if err = handler.threadUsecase.SetUnread(viewer, threadDetails); err != nil {
+			log.WithError(err).Error("thread get read state error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	// the read state is a part of the body, so the tag changes with it
+	utilities.RespTagged(ctx, threadDetails.Version, threadDetails)
 }
 
 func (handler *threadHandler) threadUpdateDetailsHandler(ctx *fasthttp.RequestCtx) {
@@ -96,40 +133,83 @@ func (handler *threadHandler) threadUpdateDetailsHandler(ctx *fasthttp.RequestCt
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedThread, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+	version, err := utilities.IfMatchVersion(ctx)
+	if err != nil {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(domain.FieldErrorArray{
+			{Field: fasthttp.HeaderIfMatch, Message: err.Error()}}))
+		return
+	}
+	parsedThread.Version = version
+
 	updatedThread, err := handler.threadUsecase.UpdateThreadDetails(slugOrId, *parsedThread)
 	if err != nil {
 		log.WithError(err).Error("thread update error")
 		if err == thread.NotFound {
 			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
 			return
+		} else if err == utilities.PreconditionFailed {
+			utilities.Resp(ctx, fasthttp.StatusPreconditionFailed, errors.JSONErrorMessage(err))
+			return
 		} else {
 			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
 			return
 		}
 	}
+	utilities.SetETag(ctx, updatedThread.Version)
 	utilities.Resp(ctx, http.StatusOK, updatedThread)
 }
 
 func (handler *threadHandler) threadGetPostsHandler(ctx *fasthttp.RequestCtx) {
 	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
-	params, err := utilities.NewArrayOutParams(ctx.QueryArgs())
-	if err != nil {
-		log.WithError(err).Error(errors.QuerystringParseError)
-		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID, "flat", "tree", "parent_tree")
+	if params != nil && params.OrderBy == post.ScoreOrder && params.Sort == "parent_tree" {
+		fieldErrors = append(fieldErrors, domain.FieldError{Field: "order_by", Message: "score order is not supported by parent_tree sort"})
+	} else if params != nil {
+		fieldErrors = append(fieldErrors, validation.Var("order_by", params.OrderBy, "oneof=created "+post.ScoreOrder)...)
+	}
+	nested := ctx.QueryArgs().GetBool("nested")
+	if params != nil && params.Sort != "tree" && params.Sort != "parent_tree" {
+		if params.MaxDepth != 0 {
+			fieldErrors = append(fieldErrors, domain.FieldError{Field: "max_depth", Message: "is supported by tree and parent_tree sorts only"})
+		}
+		if nested {
+			fieldErrors = append(fieldErrors, domain.FieldError{Field: "nested", Message: "is supported by tree and parent_tree sorts only"})
+		}
+	}
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
 		return
 	}
 
-	foundPosts, err := handler.threadUsecase.GetThreadPosts(slugOrId, *params)
+	foundPosts, page, err := handler.threadUsecase.GetThreadPosts(slugOrId, *params)
 	if err != nil {
 		log.WithError(err).Error("post find error")
 		if err == thread.NotFound {
 			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
 			return
+		} else if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
 		} else {
 			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
 			return
 		}
 	}
+	if utilities.RenderHTML(ctx) {
+		if err = handler.postUsecase.AttachHTML(post.Refs(foundPosts)); err != nil {
+			log.WithError(err).Error("post render error")
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	if nested {
+		foundPosts = post.Nest(foundPosts)
+	}
+	utilities.SetPageLinks(ctx, page)
 	utilities.Resp(ctx, fasthttp.StatusOK, foundPosts)
 }
 
@@ -143,6 +223,11 @@ func (handler *threadHandler) threadVoteHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedVote, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
 	votedThread, err := handler.threadUsecase.CreateThreadVote(slugOrId, *parsedVote)
 	if err != nil {
 		log.WithError(err).Error("vote creation error")
@@ -156,3 +241,147 @@ func (handler *threadHandler) threadVoteHandler(ctx *fasthttp.RequestCtx) {
 	}
 	utilities.Resp(ctx, fasthttp.StatusOK, votedThread)
 }
+
+func (handler *threadHandler) threadRetractVoteHandler(ctx *fasthttp.RequestCtx) {
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	nickname := string(ctx.QueryArgs().Peek("nickname"))
+	if fieldErrors := validation.Var("nickname", nickname, "required,nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	votedThread, err := handler.threadUsecase.CreateThreadVote(slugOrId, domain.Vote{Nickname: nickname})
+	if err != nil {
+		log.WithError(err).Error("vote retraction error")
+		if err == thread.NotFound || err == thread.AuthorNotExists {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		} else {
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, votedThread)
+}
+
+func (handler *threadHandler) threadGetVotesHandler(ctx *fasthttp.RequestCtx) {
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceAny)
+	viewer := utilities.Viewer(ctx)
+	utilities.VaryOnViewer(ctx)
+	fieldErrors = append(fieldErrors, validation.Var(utilities.ViewerHeader, viewer, "nickname")...)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	threadVotes, page, err := handler.threadUsecase.GetThreadVotes(slugOrId, viewer, *params)
+	if err != nil {
+		log.WithError(err).Error("thread get votes error")
+		if err == thread.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, threadVotes)
+}
+
+func (handler *threadHandler) threadMergeHandler(ctx *fasthttp.RequestCtx) {
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	parsedMerge := &domain.ThreadMerge{}
+	err := easyjson.Unmarshal(ctx.PostBody(), parsedMerge)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, http.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return
+	}
+
+	if fieldErrors := validation.Struct(parsedMerge, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	mergedThread, err := handler.threadUsecase.MergeThreads(slugOrId, utilities.NewSlugOrId(parsedMerge.Source))
+	if err != nil {
+		log.WithError(err).Error("thread merge error")
+		if err == thread.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		} else if err == thread.MergeConflict {
+			utilities.Resp(ctx, fasthttp.StatusConflict, errors.JSONErrorMessage(err))
+			return
+		} else {
+			utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+			return
+		}
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, mergedThread)
+}
+
+func (handler *threadHandler) tagGetThreadsHandler(ctx *fasthttp.RequestCtx) {
+	tag := ctx.UserValue("tag").(string)
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceDate, thread.Sorts...)
+	fieldErrors = append(fieldErrors, validation.Var("window", string(ctx.QueryArgs().Peek("window")), "oneof=day week month year all")...)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundThreads, page, err := handler.threadUsecase.GetThreadsByTag(tag, *params)
+	if err != nil {
+		log.WithError(err).Error("tag get threads error")
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundThreads)
+}
+
+func (handler *threadHandler) trendingThreadsHandler(ctx *fasthttp.RequestCtx) {
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceAny)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	foundThreads, page, err := handler.threadUsecase.GetTrendingThreads(*params)
+	if err != nil {
+		log.WithError(err).Error("trending threads error")
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(err), errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, foundThreads)
+}
+
+func (handler *threadHandler) threadMarkReadHandler(ctx *fasthttp.RequestCtx) {
+	slugOrId := utilities.NewSlugOrId(ctx.UserValue("slug_or_id").(string))
+	viewer := utilities.Viewer(ctx)
+	if fieldErrors := validation.Var(utilities.ViewerHeader, viewer, "required,nickname"); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+	marker := &domain.ReadMarker{}
+	if len(ctx.PostBody()) != 0 {
+		if err := easyjson.Unmarshal(ctx.PostBody(), marker); err != nil {
+			log.WithError(err).Error(errors.JSONUnmarshallError)
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+			return
+		}
+	}
+
+	readThread, err := handler.threadUsecase.MarkThreadRead(slugOrId, viewer, marker.Post)
+	if err != nil {
+		log.WithError(err).Error("thread mark read error")
+		if err == thread.NotFound || err == post.NotFoundError || err == user.NotExistsError {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, readThread)
+}
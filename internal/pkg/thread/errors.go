@@ -6,4 +6,5 @@ var (
 	AlreadyExists   = errors.New("thread already exists")
 	NotFound        = errors.New("thread not found")
 	AuthorNotExists = errors.New("thread author does not exist")
+	MergeConflict   = errors.New("thread can not be merged into itself")
 )
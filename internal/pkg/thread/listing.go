@@ -0,0 +1,174 @@
+package thread
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/go-openapi/strfmt"
+	"github.com/jackc/pgx"
+	"strconv"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+// Columns are the thread columns ScanRows expects
+const Columns = "id, title, author, forum, message, slug, created, votes, tags, posts, last_post_at, hot_score, last_post_id, last_post_author, version"
+
+// Listing sorts, created is the default one. Ranking sorts always go from the highest rank.
+const (
+	SortCreated = "created"
+	SortHot     = "hot"
+	SortTop     = "top"
+	SortActive  = "active"
+)
+
+// Sorts are the sort param values threads listings accept
+var Sorts = []string{SortCreated, SortHot, SortTop, SortActive}
+
+// Windows limit the top sort to threads created within the period
+var Windows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"year":  365 * 24 * time.Hour,
+}
+
+// sortKey returns the sort column of the listing and the cast of its cursor value
+func sortKey(sort string) (string, string) {
+	switch sort {
+	case SortHot:
+		return "hot_score", "::float8"
+	case SortTop:
+		return "votes", "::integer"
+	case SortActive:
+		return "last_post_at", "::timestamptz"
+	default:
+		return "created", "::timestamptz"
+	}
+}
+
+func isRanking(sort string) bool {
+	return sort == SortHot || sort == SortTop || sort == SortActive
+}
+
+func listingDesc(params utilities.ArrayOutParams) bool {
+	return params.Desc || isRanking(params.Sort)
+}
+
+// CursorScope binds cursors of a listing to its sort
+func CursorScope(scope string, params utilities.ArrayOutParams) string {
+	if isRanking(params.Sort) {
+		return scope + ":" + params.Sort
+	}
+	return scope
+}
+
+// ApplyListing adds since or cursor bounds and the (sort column, id) order of a threads listing.
+// Cursor must be decoded with CursorKeyLen, its order overrides the desc param.
+// Since is a creation date, so only the created sort uses it.
+func ApplyListing(req sq.SelectBuilder, params utilities.ArrayOutParams, cursor *utilities.Cursor) sq.SelectBuilder {
+	column, cast := sortKey(params.Sort)
+	desc := listingDesc(params)
+	if cursor != nil {
+		desc = cursor.Desc != cursor.Backward
+		if desc {
+			req = req.Where("("+column+", id) < (?"+cast+", ?)", cursor.Key[0], cursor.Key[1])
+		} else {
+			req = req.Where("("+column+", id) > (?"+cast+", ?)", cursor.Key[0], cursor.Key[1])
+		}
+	} else if params.Since != "" && column == "created" {
+		if desc {
+			req = req.Where(sq.LtOrEq{"created": params.Since})
+		} else {
+			req = req.Where(sq.GtOrEq{"created": params.Since})
+		}
+	}
+	if window, ok := Windows[params.Window]; ok && params.Sort == SortTop {
+		req = req.Where(sq.GtOrEq{"created": time.Now().Add(-window)})
+	}
+	if desc {
+		req = req.OrderBy(column+" desc", "id desc")
+	} else {
+		req = req.OrderBy(column, "id")
+	}
+	return req.Limit(uint64(params.Limit))
+}
+
+// CursorKeyLen is the length of the threads listing sort key
+const CursorKeyLen = 2
+
+func CursorKey(t domain.Thread, sort string) []string {
+	var key string
+	switch sort {
+	case SortHot:
+		key = strconv.FormatFloat(t.Hot, 'g', -1, 64)
+	case SortTop:
+		key = strconv.FormatInt(int64(t.Votes), 10)
+	case SortActive:
+		if t.LastPostAt != nil {
+			key = time.Time(*t.LastPostAt).Format(constants.TimeLayout)
+		}
+	default:
+		key = time.Time(t.Created).Format(constants.TimeLayout)
+	}
+	return []string{key, strconv.FormatInt(int64(t.ID), 10)}
+}
+
+// ListingPage puts backward page rows back into the listing order and builds its cursors
+func ListingPage(scope string, threads domain.ThreadArray, params utilities.ArrayOutParams, cursor *utilities.Cursor) *utilities.Page {
+	desc := listingDesc(params)
+	if cursor != nil {
+		desc = cursor.Desc
+		if cursor.Backward {
+			for i, j := 0, len(threads)-1; i < j; i, j = i+1, j-1 {
+				threads[i], threads[j] = threads[j], threads[i]
+			}
+		}
+	}
+	if len(threads) == 0 {
+		return &utilities.Page{}
+	}
+	return utilities.NewPage(CursorScope(scope, params), desc, cursor, len(threads), params.Limit,
+		CursorKey(threads[0], params.Sort), CursorKey(threads[len(threads)-1], params.Sort))
+}
+
+type row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan reads Columns of a single row into the thread
+func Scan(r row, t *domain.Thread) error {
+	var slug, lastPostAuthor *string
+	var lastPostID *int64
+	var lastPostAt strfmt.DateTime
+	err := r.Scan(&t.ID, &t.Title, &t.Author, &t.Forum, &t.Message, &slug, &t.Created, &t.Votes, &t.Tags,
+		&t.Posts, &lastPostAt, &t.Hot, &lastPostID, &lastPostAuthor, &t.Version)
+	if err != nil {
+		return err
+	}
+	if slug != nil {
+		t.Slug = *slug
+	}
+	if lastPostID != nil {
+		t.LastPostID = *lastPostID
+	}
+	if lastPostAuthor != nil {
+		t.LastPostAuthor = *lastPostAuthor
+	}
+	t.LastPostAt = &lastPostAt
+	return nil
+}
+
+func ScanRows(rows *pgx.Rows) (domain.ThreadArray, error) {
+	defer rows.Close()
+
+	resThreads := make(domain.ThreadArray, 0)
+	for rows.Next() {
+		var currentThread domain.Thread
+		if err := Scan(rows, &currentThread); err != nil {
+			return nil, err
+		}
+		resThreads = append(resThreads, currentThread)
+	}
+	return resThreads, rows.Err()
+}
@@ -0,0 +1,22 @@
+package thread
+
+import "strings"
+
+// NormalizeTags lowercases and trims tags dropping empty ones and duplicates,
+// nil stays nil so that updates can tell "no tags passed" from "clear tags".
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
@@ -1,15 +1,23 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/go-openapi/strfmt"
 	"github.com/jackc/pgx"
+	"sort"
 	"strconv"
+	"strings"
+	"technopark-dbms/internal/pkg/cache"
+	"technopark-dbms/internal/pkg/constants"
 	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/notification"
 	"technopark-dbms/internal/pkg/post"
 	"technopark-dbms/internal/pkg/thread"
+	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/webhook"
 	"time"
 )
 
@@ -18,9 +26,15 @@ var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 type threadUsecase struct {
 	DB     *pgx.ConnPool
 	UUCase domain.UserUsecase
+	Cache  *cache.Cache
 }
 
+// GetThreadIdAndForum is cached until the thread is merged into another one, id and forum of a thread never change
 func (t threadUsecase) GetThreadIdAndForum(s utilities.SlugOrId) (*domain.Thread, error) {
+	resThread := &domain.Thread{}
+	if t.Cache.Load(cache.ThreadKey(s), resThread) {
+		return resThread, nil
+	}
 	query := "select id, forum from threads where"
 	args := make([]interface{}, 0)
 	if s.IsSlug {
@@ -31,7 +45,6 @@ func (t threadUsecase) GetThreadIdAndForum(s utilities.SlugOrId) (*domain.Thread
 		args = append(args, s.ID)
 	}
 
-	resThread := &domain.Thread{}
 	err := t.DB.QueryRow(query, args...).
 		Scan(&resThread.ID, &resThread.Forum)
 	if err != nil {
@@ -40,6 +53,7 @@ func (t threadUsecase) GetThreadIdAndForum(s utilities.SlugOrId) (*domain.Thread
 		}
 		return nil, err
 	}
+	t.Cache.Store(cache.ThreadKey(s), resThread)
 	return resThread, nil
 }
 
@@ -57,25 +71,14 @@ func (t threadUsecase) CreatePosts(s utilities.SlugOrId, posts domain.PostArray)
 	}
 
 	now := strfmt.DateTime(time.Now())
-	//for i, _ := range posts {
-	//	posts[i].Created = now
-	//	posts[i].Thread = threadInfo.ID
-	//	posts[i].Forum = threadInfo.Forum
-	//}
-
-	//query := "insert into posts(parent, author, message, is_edited, thread, created, forum) values ($1, $2, $3, $4, $5, $6, $7) returning id;"
-	req := psql.Insert("posts(parent, author, message, is_edited, thread, created, forum)")
-	userReq := psql.Insert("f_u(f, u)")
 	for i, _ := range posts {
 		posts[i].Created = now
 		posts[i].Thread = threadInfo.ID
 		posts[i].Forum = threadInfo.Forum
-		req = req.Values(posts[i].Parent, posts[i].Author, posts[i].Message, posts[i].IsEdited, posts[i].Thread, posts[i].Created, posts[i].Forum)
-		userReq = userReq.Values(threadInfo.Forum, posts[i].Author)
+		if posts[i].Quotes == nil {
+			posts[i].Quotes = make([]int64, 0)
+		}
 	}
-	req = req.Suffix("returning id")
-	userReq = userReq.Suffix("on conflict do nothing")
-	query, args, _ := req.ToSql()
 
 	tx, err := t.DB.Begin()
 	if err != nil {
@@ -83,49 +86,199 @@ func (t threadUsecase) CreatePosts(s utilities.SlugOrId, posts domain.PostArray)
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.Query(query, args...)
-	if err != nil {
+	if err = post.ResolveMentions(tx, posts); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	for i := 0; rows.Next(); i++ {
-		err := rows.Scan(&posts[i].ID)
-		if err != nil {
-			return nil, err
-		}
+	// quoted posts may be anywhere, so they are checked as posts rather than within the thread
+	if err = post.ResolveQuotes(tx, posts); err != nil {
+		return nil, err
 	}
-	if rows.Err() != nil {
-		if pgErr, ok := rows.Err().(pgx.PgError); ok {
+	if len(posts) >= constants.CopyPostsBatch {
+		err = copyPosts(tx, posts)
+	} else {
+		err = insertPosts(tx, posts)
+	}
+	if err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok {
 			if pgErr.Code == "66666" {
 				return nil, post.InvalidParentError
 			}
 			if pgErr.Code == "23503" {
 				return nil, thread.AuthorNotExists
 			}
-			return nil, rows.Err()
 		}
-	}
-	query = "update forums set posts = posts + $1 where slug = $2;"
-	_, err = tx.Exec(query, len(posts), threadInfo.Forum)
-	if err != nil {
 		return nil, err
 	}
 
-	query, args, _ = userReq.ToSql()
-	_, err = tx.Exec(query, args...)
-	if err != nil {
+	if err = afterPostsInsert(tx, threadInfo, posts, now); err != nil {
+		return nil, err
+	}
+	if err = post.SaveMentions(tx, posts); err != nil {
+		return nil, err
+	}
+	if err = post.SaveQuotes(tx, posts); err != nil {
+		return nil, err
+	}
+	if err = notification.Notify(tx, threadInfo.ID, posts); err != nil {
+		return nil, err
+	}
+	if err = webhook.Enqueue(tx, webhook.PostsCreated, posts); err != nil {
 		return nil, err
 	}
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
+	t.Cache.Invalidate(cache.ForumKey(threadInfo.Forum))
 	return posts, nil
 }
 
+// insertPosts inserts a batch with a single statement, update_post_ways trigger builds the ways
+func insertPosts(tx *pgx.Tx, posts domain.PostArray) error {
+	req := psql.Insert("posts(parent, author, message, message_html, is_edited, thread, created, forum, mentions, quotes)")
+	for _, p := range posts {
+		req = req.Values(p.Parent, p.Author, p.Message, post.RenderMessage(p.Message), p.IsEdited, p.Thread, p.Created, p.Forum, p.Mentions, p.Quotes)
+	}
+	query, args, err := req.Suffix("returning id").ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for i := 0; rows.Next(); i++ {
+		if err = rows.Scan(&posts[i].ID); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// copyPosts loads a big batch with COPY, which has no parameters limit and skips per row parent lookups.
+// Ids are taken from the posts sequence beforehand, so the ways are built here
+// and update_post_ways trigger leaves posts that already have a way as is.
+func copyPosts(tx *pgx.Tx, posts domain.PostArray) error {
+	rows, err := tx.Query("select nextval('posts_id_seq') from generate_series(1, $1);", len(posts))
+	if err != nil {
+		return err
+	}
+	ids := make([]int64, 0, len(posts))
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+	// ids go in the batch order, so a parent from the same batch always precedes its replies
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	batchIndex := make(map[int64]int, len(posts))
+	for i := range posts {
+		posts[i].ID = ids[i]
+		batchIndex[ids[i]] = i
+	}
+	parentIds := make([]int64, 0)
+	for _, p := range posts {
+		if _, inBatch := batchIndex[p.Parent]; p.Parent != 0 && !inBatch {
+			parentIds = append(parentIds, p.Parent)
+		}
+	}
+	parentWays := make(map[int64][]int64, len(parentIds))
+	if len(parentIds) != 0 {
+		rows, err = tx.Query("select id, way from posts where thread = $1 and id = any($2);", posts[0].Thread, parentIds)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id int64
+			var way []int64
+			if err = rows.Scan(&id, &way); err != nil {
+				rows.Close()
+				return err
+			}
+			parentWays[id] = way
+		}
+		rows.Close()
+		if rows.Err() != nil {
+			return rows.Err()
+		}
+	}
+
+	ways := make([][]int64, len(posts))
+	copyRows := make([][]interface{}, len(posts))
+	for i, p := range posts {
+		var parentWay []int64
+		if p.Parent == 0 {
+			parentWay = []int64{0}
+		} else if j, inBatch := batchIndex[p.Parent]; inBatch && j < i {
+			parentWay = ways[j]
+		} else if way, found := parentWays[p.Parent]; found {
+			parentWay = way
+		} else {
+			return post.InvalidParentError
+		}
+		ways[i] = append(append(make([]int64, 0, len(parentWay)+1), parentWay...), p.ID)
+		copyRows[i] = []interface{}{p.ID, p.Parent, p.Author, p.Message, post.RenderMessage(p.Message), p.IsEdited, int64(p.Thread), time.Time(p.Created), p.Forum, p.Mentions, p.Quotes, ways[i]}
+	}
+
+	columns := []string{"id", "parent", "author", "message", "message_html", "is_edited", "thread", "created", "forum", "mentions", "quotes", "way"}
+	_, err = tx.CopyFrom(pgx.Identifier{"posts"}, columns, pgx.CopyFromRows(copyRows))
+	return err
+}
+
+// afterPostsInsert updates counters, last activity and forum users for a created batch, whatever way it was inserted
+func afterPostsInsert(tx *pgx.Tx, threadInfo *domain.Thread, posts domain.PostArray, now strfmt.DateTime) error {
+	// concurrent batches may commit in any order, so the last post is replaced only by a newer one
+	lastPost := posts[0]
+	authors := make([]string, 0, len(posts))
+	for _, p := range posts {
+		if p.ID > lastPost.ID {
+			lastPost = p
+		}
+		authors = append(authors, p.Author)
+	}
+	query := `update forums set posts = posts + $1,
+		last_post_author = case when coalesce(last_post_id, 0) < $3 then $4 else last_post_author end,
+		last_post_thread = case when coalesce(last_post_id, 0) < $3 then $5 else last_post_thread end,
+		last_post_at = case when coalesce(last_post_id, 0) < $3 then $6 else last_post_at end,
+		last_post_id = greatest(last_post_id, $3)
+		where slug = $2;`
+	_, err := tx.Exec(query, len(posts), threadInfo.Forum, lastPost.ID, lastPost.Author, threadInfo.ID, now)
+	if err != nil {
+		return err
+	}
+	query = `update threads set posts = posts + $1,
+		last_post_author = case when coalesce(last_post_id, 0) < $3 then $4 else last_post_author end,
+		last_post_at = case when coalesce(last_post_id, 0) < $3 then $5 else last_post_at end,
+		last_post_id = greatest(last_post_id, $3)
+		where id = $2;`
+	_, err = tx.Exec(query, len(posts), threadInfo.ID, lastPost.ID, lastPost.Author, now)
+	if err != nil {
+		return err
+	}
+
+	query = "insert into f_u(f, u) select $1, u from unnest($2::text[]) u on conflict do nothing;"
+	if _, err = tx.Exec(query, threadInfo.Forum, authors); err != nil {
+		return err
+	}
+	// posting subscribes to the thread, users who left it stay unsubscribed
+	query = "insert into thread_subscriptions(thread, username) select $1, u from unnest($2::text[]) u on conflict do nothing;"
+	_, err = tx.Exec(query, threadInfo.ID, authors)
+	return err
+}
+
 func (t threadUsecase) GetThreadDetails(s utilities.SlugOrId) (*domain.Thread, error) {
-	query := "select id, title, author, message, votes, forum, slug, created from threads where "
+	query := "select " + thread.Columns + " from threads where "
 	args := make([]interface{}, 0)
 	if s.IsSlug {
 		query += "slug = $1;"
@@ -135,66 +288,73 @@ func (t threadUsecase) GetThreadDetails(s utilities.SlugOrId) (*domain.Thread, e
 		args = append(args, s.ID)
 	}
 
-	var slug *string
 	resThread := &domain.Thread{}
-	err := t.DB.QueryRow(query, args...).
-		Scan(&resThread.ID, &resThread.Title, &resThread.Author, &resThread.Message, &resThread.Votes, &resThread.Forum, &slug, &resThread.Created)
-
+	err := thread.Scan(t.DB.QueryRow(query, args...), resThread)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, thread.NotFound
 		}
 		return nil, err
 	}
-	if slug != nil {
-		resThread.Slug = *slug
-	}
 	return resThread, nil
 }
 
+// UpdateThreadDetails applies non empty fields of the update.
+// Non zero Version of the update must be the current one, so concurrent edits are not overwritten.
 func (t threadUsecase) UpdateThreadDetails(s utilities.SlugOrId, threadUpdate domain.Thread) (*domain.Thread, error) {
 	threadDetails, err := t.GetThreadDetails(s)
 	if err != nil {
 		return nil, err
 	}
-	if threadUpdate.Message == "" && threadUpdate.Title == "" {
+	if threadUpdate.Version != 0 && threadUpdate.Version != threadDetails.Version {
+		return nil, utilities.PreconditionFailed
+	}
+	tags := thread.NormalizeTags(threadUpdate.Tags)
+	if threadUpdate.Message == "" && threadUpdate.Title == "" && tags == nil {
 		return threadDetails, nil
 	}
 
-	updateThreadQuery := "update threads set title=coalesce(nullif($1, ''), title), message=coalesce(nullif($2, ''), message) where id = $3;"
-	_, err = t.DB.Exec(updateThreadQuery, threadUpdate.Title, threadUpdate.Message, threadDetails.ID)
+	updateThreadQuery := "update threads set title=coalesce(nullif($1, ''), title), message=coalesce(nullif($2, ''), message), tags=coalesce($3, tags) " +
+		"where id = $4 and ($5 = 0 or version = $5) returning " + thread.Columns + ";"
+	updatedThread := &domain.Thread{}
+	err = thread.Scan(t.DB.QueryRow(updateThreadQuery, threadUpdate.Title, threadUpdate.Message, tags, threadDetails.ID, threadUpdate.Version), updatedThread)
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, utilities.PreconditionFailed
+		}
 		return nil, err
 	}
-	if threadUpdate.Title != "" {
-		threadDetails.Title = threadUpdate.Title
-	}
-	if threadUpdate.Message != "" {
-		threadDetails.Message = threadUpdate.Message
-	}
-	return threadDetails, nil
+	return updatedThread, nil
 }
 
-func parentPostsQuery(id int32, limit int, since int64, desc bool) (string, []interface{}) {
-	order, s := "asc", " > "
+// parentPostsQuery pages over root posts, a backward page takes the roots
+// before since in the reversed order but still returns them in the listing one
+func parentPostsQuery(id int32, limit int, since int64, desc bool, backward bool, sinceIsRoot bool, maxDepth int32) (string, []interface{}) {
+	order, rootOrder, s := "asc", "asc", " > "
 	if desc {
 		order = "desc"
-	} else {
-		order = "asc"
 	}
-	if desc && since != 0 {
+	if desc != backward {
+		rootOrder = "desc"
+	}
+	if desc != backward && since != 0 {
 		s = " < "
-	} else {
-		s = " > "
 	}
 	args := []interface{}{id, limit}
-	query := `select p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created
-			from posts p where p.way[2] in (select id from posts where thread = $1 and way[3] is null`
+	roots := "select id from posts where thread = $1 and way[3] is null"
 	if since != 0 {
-		query += " and way[2] " + s + "(select way[2] from posts where id = $3)"
 		args = append(args, since)
+		if sinceIsRoot {
+			roots += " and id " + s + " $3"
+		} else {
+			roots += " and way[2] " + s + "(select way[2] from posts where id = $3)"
+		}
 	}
-	query += " order by id " + order + " limit $2) order by p.way[2] " + order + ",p.way asc, p.id asc"
+	roots += " order by id " + rootOrder + " limit $2"
+	depthCondition, repliesColumn, args := post.DepthLimit("array_length(p.way, 1) - 1", int(maxDepth), args)
+	query := "select " + post.Columns + ", p.way[2]::text, " + repliesColumn +
+		" from posts p where p.way[2] in (" + roots + ")" + depthCondition +
+		" order by p.way[2] " + order + ",p.way asc, p.id asc"
 	return query, args
 }
 
@@ -204,7 +364,7 @@ func flatPostsQuery(id int32, limit int, since int64, desc bool) (string, []inte
 		order, s = "desc", " < "
 	}
 	args := []interface{}{id, limit}
-	query := `select p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created
+	query := "select " + post.Columns + `, p.id::text, 0
 				from posts p where p.thread = $1`
 	if since != 0 {
 		query += " and p.id " + s + " $3"
@@ -214,7 +374,9 @@ func flatPostsQuery(id int32, limit int, since int64, desc bool) (string, []inte
 	return query, args
 }
 
-func treePostsQuery(id int32, limit int, since int64, desc bool) (string, []interface{}) {
+// treePostsQuery orders by way, which is unique and ends with the post id,
+// so the post id is enough to restore the whole sort key
+func treePostsQuery(id int32, limit int, since int64, desc bool, maxDepth int32) (string, []interface{}) {
 	order, s := "asc", " > "
 	if desc {
 		order = "desc"
@@ -223,110 +385,600 @@ func treePostsQuery(id int32, limit int, since int64, desc bool) (string, []inte
 		s = " < "
 	}
 	args := []interface{}{id, limit}
-	query := `select p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created
-		from posts p where p.thread = $1`
+	condition := ""
 	if since != 0 {
-		query += "and way " + s + "(select way from posts where id = $3)"
+		condition += " and way " + s + "(select way from posts where id = $3)"
+		args = append(args, since)
+	}
+	depthCondition, repliesColumn, args := post.DepthLimit("array_length(p.way, 1) - 1", int(maxDepth), args)
+	query := "select " + post.Columns + ", p.id::text, " + repliesColumn +
+		" from posts p where p.thread = $1" + condition + depthCondition +
+		" order by p.way " + order + ", p.created " + order + ", p.id asc limit $2 "
+	return query, args
+}
+
+// scorePostsQuery orders posts with higher votes first and ties broken by id.
+// The tree mode orders siblings that way and keeps replies under their parents.
+// Both sort keys are bigint arrays of (-votes, id) pairs, from the root in the tree mode,
+// so the key itself serves as the cursor.
+func scorePostsQuery(id int32, limit int, since int64, sinceKey string, desc bool, tree bool, maxDepth int32) (string, []interface{}) {
+	order, s := "asc", " > "
+	if desc {
+		order, s = "desc", " < "
+	}
+	args := []interface{}{id, limit}
+	columns := "id, parent, author, message, is_edited, forum, thread, created, votes, mentions, quotes, version"
+	query := "with sorted as (select " + columns + ", array[-votes, id] as sort_key from posts where thread = $1)"
+	if tree {
+		query = "with recursive sorted as (select " + columns + ", array[-votes, id] as sort_key from posts where thread = $1 and parent = 0" +
+			" union all select c." + strings.ReplaceAll(columns, ", ", ", c.") + ", s.sort_key || array[-c.votes, c.id]" +
+			" from posts c join sorted s on c.parent = s.id where c.thread = $1)"
+	}
+	condition := " where true"
+	if sinceKey != "" {
+		condition += " and p.sort_key" + s + "$3::bigint[]"
+		args = append(args, sinceKey)
+	} else if since != 0 {
+		condition += " and p.sort_key" + s + "(select sort_key from sorted where id = $3)"
 		args = append(args, since)
 	}
-	query += "order by p.way " + order + ", p.created " + order + ", p.id asc limit $2 "
+	repliesColumn := "0"
+	if tree {
+		var depthCondition string
+		depthCondition, repliesColumn, args = post.DepthLimit("array_length(p.sort_key, 1) / 2", int(maxDepth), args)
+		condition += depthCondition
+	}
+	query += " select " + post.Columns + ", p.sort_key::text, " + repliesColumn + " from sorted p" + condition +
+		" order by p.sort_key " + order + " limit $2"
 	return query, args
 }
 
-func generateGetPostsQuery(threadId int32, params utilities.ArrayOutParams) (string, []interface{}, error) {
-	since := int64(0)
-	if params.Since != "" {
-		parsedSince, err := strconv.ParseInt(params.Since, 10, 64)
+func postsSort(sort string) string {
+	if sort != "tree" && sort != "parent_tree" {
+		return "flat"
+	}
+	return sort
+}
+
+func postsCursorScope(params utilities.ArrayOutParams) string {
+	if params.OrderBy == post.ScoreOrder {
+		return "thread_posts:" + postsSort(params.Sort) + ":" + post.ScoreOrder
+	}
+	return "thread_posts:" + postsSort(params.Sort)
+}
+
+func generateGetPostsQuery(threadId int32, params utilities.ArrayOutParams) (string, []interface{}, *utilities.Cursor, error) {
+	sort := postsSort(params.Sort)
+	cursor, err := utilities.ParamsCursor(params, postsCursorScope(params), 1)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	since, desc, backward := int64(0), params.Desc, false
+	sinceValue := params.Since
+	if cursor != nil {
+		sinceValue, desc, backward = cursor.Key[0], cursor.Desc, cursor.Backward
+	}
+	if params.OrderBy == post.ScoreOrder && cursor != nil {
+		query, args := scorePostsQuery(threadId, int(params.Limit), 0, sinceValue, desc != backward, sort == "tree", params.MaxDepth)
+		return query, args, cursor, nil
+	}
+	if sinceValue != "" {
+		parsedSince, err := strconv.ParseInt(sinceValue, 10, 64)
 		if err != nil {
-			return "", nil, err
+			return "", nil, nil, err
 		}
 		since = parsedSince
 	}
 	var query string
 	var args []interface{}
-	switch params.Sort {
-	case "flat":
-		query, args = flatPostsQuery(threadId, int(params.Limit), since, params.Desc)
-	case "tree":
-		query, args = treePostsQuery(threadId, int(params.Limit), since, params.Desc)
-	case "parent_tree":
-		query, args = parentPostsQuery(threadId, int(params.Limit), since, params.Desc)
+	switch {
+	case params.OrderBy == post.ScoreOrder:
+		query, args = scorePostsQuery(threadId, int(params.Limit), since, "", desc, sort == "tree", params.MaxDepth)
+	case sort == "tree":
+		query, args = treePostsQuery(threadId, int(params.Limit), since, desc != backward, params.MaxDepth)
+	case sort == "parent_tree":
+		query, args = parentPostsQuery(threadId, int(params.Limit), since, desc, backward, cursor != nil, params.MaxDepth)
 	default:
-		query, args = flatPostsQuery(threadId, int(params.Limit), since, params.Desc)
+		query, args = flatPostsQuery(threadId, int(params.Limit), since, desc != backward)
 	}
-	return query, args, nil
+	return query, args, cursor, nil
 }
 
-func (t threadUsecase) GetThreadPosts(s utilities.SlugOrId, params utilities.ArrayOutParams) (domain.PostArray, error) {
+func (t threadUsecase) GetThreadPosts(s utilities.SlugOrId, params utilities.ArrayOutParams) (domain.PostArray, *utilities.Page, error) {
 	threadDetails, err := t.GetThreadIdAndForum(s)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	getPostsQuery, args, err := generateGetPostsQuery(threadDetails.ID, params)
+	getPostsQuery, args, cursor, err := generateGetPostsQuery(threadDetails.ID, params)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	rows, err := t.DB.Query(getPostsQuery, args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
-	// p.id, p.parent, p.author, p.message, p.is_edited, p.forum, p.thread, p.created
+	// post columns, cursor key, replies count
 	resPosts := make(domain.PostArray, 0)
+	cursorKeys := make([]string, 0)
 	for rows.Next() {
 		var p domain.Post
-		//var created *strfmt.DateTime
-		err := rows.Scan(&p.ID, &p.Parent, &p.Author, &p.Message, &p.IsEdited, &p.Forum, &p.Thread, &p.Created)
+		var cursorKey string
+		var repliesCount int32
+		err := post.Scan(rows, &p, &cursorKey, &repliesCount)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		//if created != nil {
-		//	p.Created = *created
-		//}
+		post.SetRepliesCount(&p, repliesCount)
 		resPosts = append(resPosts, p)
+		cursorKeys = append(cursorKeys, cursorKey)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+	if err = post.LoadQuoted(t.DB, post.Refs(resPosts)); err != nil {
+		return nil, nil, err
+	}
+
+	desc := params.Desc
+	if cursor != nil {
+		desc = cursor.Desc
+		if cursor.Backward && postsSort(params.Sort) != "parent_tree" {
+			for i, j := 0, len(resPosts)-1; i < j; i, j = i+1, j-1 {
+				resPosts[i], resPosts[j] = resPosts[j], resPosts[i]
+				cursorKeys[i], cursorKeys[j] = cursorKeys[j], cursorKeys[i]
+			}
+		}
+	}
+	page := &utilities.Page{}
+	if len(resPosts) != 0 {
+		// parent_tree limit counts roots, so the page is full if it has limit distinct roots
+		count := len(resPosts)
+		if postsSort(params.Sort) == "parent_tree" {
+			count = countDistinct(cursorKeys)
+		}
+		page = utilities.NewPage(postsCursorScope(params), desc, cursor, count, params.Limit,
+			[]string{cursorKeys[0]}, []string{cursorKeys[len(cursorKeys)-1]})
 	}
+	return resPosts, page, nil
+}
 
-	return resPosts, nil
+func countDistinct(values []string) int {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		seen[v] = true
+	}
+	return len(seen)
 }
 
+// Vote statements return the thread total recalculated with the change of the vote.
+// The total is read from the statement snapshot, which does not see what vote triggers do, so the change is added to it.
+const (
+	castThreadVoteQuery = `with prev as (select voice from votes where thread = $1 and username = $2),
+     cast_vote as (insert into votes(thread, username, voice) values ($1, $2, $3)
+         on conflict (thread, username) do update set voice = excluded.voice where votes.voice <> excluded.voice
+         returning voice)
+select t.votes + coalesce((select voice from cast_vote), (select voice from prev), 0) - coalesce((select voice from prev), 0), true
+from threads t where t.id = $1;`
+	retractThreadVoteQuery = `with retracted as (delete from votes where thread = $1 and username = $2 returning voice)
+select t.votes - coalesce((select voice from retracted), 0), exists(select 1 from users where nickname = $2)
+from threads t where t.id = $1;`
+)
+
+// CreateThreadVote upserts the vote of the user, voice 0 retracts it.
+// Vote triggers keep threads.votes equal to the sum of the thread votes.
 func (t threadUsecase) CreateThreadVote(s utilities.SlugOrId, vote domain.Vote) (*domain.Thread, error) {
-	threadDetails, err := t.GetThreadDetails(s)
+	threadInfo, err := t.GetThreadIdAndForum(s)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.DB.Begin()
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	var currentVoice int32
-	getVoteQuery := "select voice from votes where thread = $1 and username = $2;"
-	err = t.DB.QueryRow(getVoteQuery, threadDetails.ID, vote.Nickname).Scan(&currentVoice)
+	var votes, voice int32
+	var userExists bool
+	if vote.Voice != nil {
+		voice = *vote.Voice
+	}
+	if voice == 0 {
+		err = tx.QueryRow(retractThreadVoteQuery, threadInfo.ID, vote.Nickname).Scan(&votes, &userExists)
+	} else {
+		err = tx.QueryRow(castThreadVoteQuery, threadInfo.ID, vote.Nickname, voice).Scan(&votes, &userExists)
+	}
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			newVoteQuery := "insert into votes(thread, username, voice) values ($1, $2, $3);"
-			_, err = t.DB.Exec(newVoteQuery, threadDetails.ID, vote.Nickname, vote.Voice)
-			if err != nil {
+		if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+			if pgErr.ConstraintName == "votes_username_fkey" {
 				return nil, thread.AuthorNotExists
 			}
-			threadDetails.Votes += vote.Voice
-			return threadDetails, nil
+			return nil, thread.NotFound
 		}
+		if err == pgx.ErrNoRows {
+			return nil, thread.NotFound
+		}
+		return nil, err
+	}
+	if !userExists {
+		return nil, thread.AuthorNotExists
+	}
+	voteCast := domain.VoteCast{Nickname: vote.Nickname, Voice: voice, Thread: threadInfo.ID, Votes: votes}
+	if err = webhook.Enqueue(tx, webhook.VoteCast, voteCast); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
-	updateVoteQuery := "update votes set voice=$3 where thread=$1 and username=$2"
-	_, err = t.DB.Exec(updateVoteQuery, threadDetails.ID, vote.Nickname, vote.Voice)
+
+	threadDetails, err := t.GetThreadDetails(utilities.SlugOrId{ID: threadInfo.ID})
 	if err != nil {
 		return nil, err
 	}
-	if vote.Voice != currentVoice {
-		threadDetails.Votes = threadDetails.Votes - currentVoice + vote.Voice
+	// vote triggers change the karma of the thread author
+	t.Cache.Invalidate(cache.UserKey(threadDetails.Author))
+	threadDetails.Votes = votes
+	return threadDetails, nil
+}
+
+// MergeThreads moves every post and vote of the source thread into the target one
+// and removes the source thread. Post ways consist of global post ids,
+// so moved subtrees stay valid for tree and parent_tree sorts as is.
+// Posts keep their ids, which order flat pages and roots of tree pages, so moved roots are not appended
+// after the target ones but interleave with them by creation. Pages of the target read before the merge
+// miss older moved posts, clients paging it are expected to reload it from the start.
+func (t threadUsecase) MergeThreads(target utilities.SlugOrId, source utilities.SlugOrId) (*domain.Thread, error) {
+	targetInfo, err := t.GetThreadIdAndForum(target)
+	if err != nil {
+		return nil, err
+	}
+	sourceInfo, err := t.GetThreadIdAndForum(source)
+	if err != nil {
+		return nil, err
+	}
+	if targetInfo.ID == sourceInfo.ID {
+		return nil, thread.MergeConflict
+	}
+
+	tx, err := t.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// locking in id order so that concurrent merges of the same pair can't deadlock
+	lockedThreads := 0
+	rows, err := tx.Query("select id from threads where id in ($1, $2) order by id for update;", targetInfo.ID, sourceInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		lockedThreads++
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	if lockedThreads != 2 {
+		return nil, thread.NotFound
+	}
+
+	query := "update posts set thread = $1, forum = $2 where thread = $3;"
+	tag, err := tx.Exec(query, targetInfo.ID, targetInfo.Forum, sourceInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	movedPosts := tag.RowsAffected()
+
+	if _, err = tx.Exec(thread.RefreshActivityQuery, targetInfo.ID, sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(thread.RefreshForumActivityQuery, targetInfo.Forum, sourceInfo.Forum); err != nil {
+		return nil, err
+	}
+
+	if movedPosts != 0 && targetInfo.Forum != sourceInfo.Forum {
+		query = "update forums set posts = posts - $1 where slug = $2;"
+		if _, err = tx.Exec(query, movedPosts, sourceInfo.Forum); err != nil {
+			return nil, err
+		}
+		query = "update forums set posts = posts + $1 where slug = $2;"
+		if _, err = tx.Exec(query, movedPosts, targetInfo.Forum); err != nil {
+			return nil, err
+		}
+		query = "insert into f_u(f, u) select distinct $1::citext, author from posts where thread = $2 on conflict do nothing;"
+		if _, err = tx.Exec(query, targetInfo.Forum, targetInfo.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// votes of users who already voted for the target thread are dropped,
+	// new_vote_set trigger adds the rest to the target thread votes
+	query = "insert into votes(thread, username, voice) select $1, username, voice from votes where thread = $2 on conflict do nothing;"
+	if _, err = tx.Exec(query, targetInfo.ID, sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec("delete from votes where thread = $1;", sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	// subscriptions of the source thread follow its posts, the rest go with the thread
+	query = "insert into thread_subscriptions(thread, username, active) select $1, username, active from thread_subscriptions where thread = $2 on conflict do nothing;"
+	if _, err = tx.Exec(query, targetInfo.ID, sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	// read markers of the source thread follow its posts, a reader of both threads keeps the furthest place
+	query = "insert into read_markers(thread, username, last_read) select $1, username, last_read from read_markers where thread = $2 " +
+		"on conflict (username, thread) do update set last_read = greatest(read_markers.last_read, excluded.last_read);"
+	if _, err = tx.Exec(query, targetInfo.ID, sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	// the target keeps its tags in their order followed by the new ones of the source
+	query = "update threads set tags = array(select u.tag from unnest(threads.tags || s.tags) with ordinality u(tag, i) group by u.tag order by min(u.i)) " +
+		"from threads s where threads.id = $1 and s.id = $2 and not threads.tags @> s.tags;"
+	if _, err = tx.Exec(query, targetInfo.ID, sourceInfo.ID); err != nil {
+		return nil, err
+	}
+	var sourceSlug *string
+	var sourceAuthor string
+	err = tx.QueryRow("delete from threads where id = $1 returning slug, author;", sourceInfo.ID).Scan(&sourceSlug, &sourceAuthor)
+	if err != nil {
+		return nil, err
+	}
+	query = "update forums set threads = threads - 1 where slug = $1;"
+	if _, err = tx.Exec(query, sourceInfo.Forum); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	staleKeys := []string{cache.ThreadIDKey(sourceInfo.ID), cache.ForumKey(targetInfo.Forum), cache.ForumKey(sourceInfo.Forum), cache.UserKey(sourceAuthor)}
+	if sourceSlug != nil {
+		staleKeys = append(staleKeys, cache.ThreadSlugKey(*sourceSlug))
+	}
+	targetDetails, err := t.GetThreadDetails(utilities.SlugOrId{ID: targetInfo.ID})
+	if err == nil {
+		// moved votes change the karma of both thread authors
+		staleKeys = append(staleKeys, cache.UserKey(targetDetails.Author))
+	}
+	t.Cache.Invalidate(staleKeys...)
+	return targetDetails, err
+}
+
+const tagThreadsCursorScope = "tag_threads"
+
+func (t threadUsecase) GetThreadsByTag(tag string, params utilities.ArrayOutParams) (domain.ThreadArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, thread.CursorScope(tagThreadsCursorScope, params), thread.CursorKeyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	req := psql.Select(thread.Columns).From("threads").
+		Where("tags @> array[?]", strings.ToLower(strings.TrimSpace(tag)))
+	query, args, err := thread.ApplyListing(req, params, cursor).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := t.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	resThreads, err := thread.ScanRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resThreads, thread.ListingPage(tagThreadsCursorScope, resThreads, params, cursor), nil
+}
+
+const trendingCursorScope = "trending_threads"
+
+// GetTrendingThreads ranks threads of every forum by the hot score
+func (t threadUsecase) GetTrendingThreads(params utilities.ArrayOutParams) (domain.ThreadArray, *utilities.Page, error) {
+	params.Sort = thread.SortHot
+	cursor, err := utilities.ParamsCursor(params, thread.CursorScope(trendingCursorScope, params), thread.CursorKeyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	query, args, err := thread.ApplyListing(psql.Select(thread.Columns).From("threads"), params, cursor).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := t.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	resThreads, err := thread.ScanRows(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resThreads, thread.ListingPage(trendingCursorScope, resThreads, params, cursor), nil
+}
+
+const votersCursorScope = "thread_voters"
+
+// GetThreadVotes returns the vote breakdown of the thread with a page of its voters ordered by nickname.
+// ViewerVoice is the vote of the viewer, it is left zero for anonymous requests.
+func (t threadUsecase) GetThreadVotes(s utilities.SlugOrId, viewer string, params utilities.ArrayOutParams) (*domain.ThreadVotes, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, votersCursorScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	threadInfo, err := t.GetThreadIdAndForum(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the breakdown and the page are read from one snapshot, so the totals agree with the voters
+	tx, err := t.DB.BeginEx(context.Background(), &pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	res := &domain.ThreadVotes{}
+	query := "select count(*) filter (where voice > 0), count(*) filter (where voice < 0), coalesce(sum(voice), 0), " +
+		"coalesce((select voice from votes where thread = $1 and username = $2), 0) from votes where thread = $1;"
+	err = tx.QueryRow(query, threadInfo.ID, viewer).Scan(&res.Upvotes, &res.Downvotes, &res.Score, &res.ViewerVoice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	desc, since := params.Desc, params.Since
+	if cursor != nil {
+		desc, since = cursor.Desc != cursor.Backward, cursor.Key[0]
+	}
+	req := psql.Select("username, voice").From("votes").Where(sq.Eq{"thread": threadInfo.ID})
+	if desc {
+		if since != "" {
+			req = req.Where(sq.Lt{"username": since})
+		}
+		req = req.OrderBy("username desc")
+	} else {
+		if since != "" {
+			req = req.Where(sq.Gt{"username": since})
+		}
+		req = req.OrderBy("username")
+	}
+	query, args, err := req.Limit(uint64(params.Limit)).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	res.Voters = make(domain.VoteArray, 0)
+	for rows.Next() {
+		var currentVote domain.Vote
+		var voice int32
+		if err = rows.Scan(&currentVote.Nickname, &voice); err != nil {
+			return nil, nil, err
+		}
+		currentVote.Voice = &voice
+		res.Voters = append(res.Voters, currentVote)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+
+	desc = params.Desc
+	if cursor != nil {
+		desc = cursor.Desc
+		if cursor.Backward {
+			for i, j := 0, len(res.Voters)-1; i < j; i, j = i+1, j-1 {
+				res.Voters[i], res.Voters[j] = res.Voters[j], res.Voters[i]
+			}
+		}
+	}
+	page := &utilities.Page{}
+	if len(res.Voters) != 0 {
+		page = utilities.NewPage(votersCursorScope, desc, cursor, len(res.Voters), params.Limit,
+			[]string{res.Voters[0].Nickname}, []string{res.Voters[len(res.Voters)-1].Nickname})
+	}
+	return res, page, nil
+}
+
+// unreadQuery counts posts after the read marker of the viewer in every thread, first_unread is zero when all are read
+const unreadQuery = `select t.id,
+       (select count(*)::integer from posts p where p.thread = t.id and p.id > coalesce(m.last_read, 0)),
+       coalesce((select min(p.id) from posts p where p.thread = t.id and p.id > coalesce(m.last_read, 0)), 0)
+from threads t
+         left join read_markers m on m.thread = t.id and m.username = $1
+where t.id = any($2);`
+
+// SetUnread fills the read state of the viewer into the threads, it does nothing for anonymous requests
+func (t threadUsecase) SetUnread(viewer string, threads ...*domain.Thread) error {
+	if viewer == "" || len(threads) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(threads))
+	byID := make(map[int64][]*domain.Thread, len(threads))
+	for _, currentThread := range threads {
+		ids = append(ids, int64(currentThread.ID))
+		byID[int64(currentThread.ID)] = append(byID[int64(currentThread.ID)], currentThread)
+	}
+
+	rows, err := t.DB.Query(unreadQuery, viewer, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, firstUnread int64
+		var unread int32
+		if err = rows.Scan(&id, &unread, &firstUnread); err != nil {
+			return err
+		}
+		for _, currentThread := range byID[id] {
+			count := unread
+			currentThread.UnreadCount, currentThread.FirstUnreadPostID = &count, firstUnread
+		}
+	}
+	return rows.Err()
+}
+
+// MarkThreadRead moves the read marker of the viewer up to the post of the thread or up to its last post when postID is zero.
+// The marker never moves back, so marking an older post read keeps the thread as it is.
+func (t threadUsecase) MarkThreadRead(s utilities.SlugOrId, viewer string, postID int64) (*domain.Thread, error) {
+	threadInfo, err := t.GetThreadIdAndForum(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastRead *int64
+	if postID == 0 {
+		err = t.DB.QueryRow("select last_post_id from threads where id = $1;", threadInfo.ID).Scan(&lastRead)
+	} else {
+		err = t.DB.QueryRow("select id from posts where id = $1 and thread = $2;", postID, threadInfo.ID).Scan(&lastRead)
+	}
+	if err == pgx.ErrNoRows {
+		if postID == 0 {
+			return nil, thread.NotFound
+		}
+		return nil, post.NotFoundError
+	} else if err != nil {
+		return nil, err
+	}
+
+	// a thread without posts has nothing to read
+	if lastRead != nil {
+		query := "insert into read_markers(thread, username, last_read) values ($1, $2, $3) " +
+			"on conflict (username, thread) do update set last_read = greatest(read_markers.last_read, excluded.last_read);"
+		if _, err = t.DB.Exec(query, threadInfo.ID, viewer, *lastRead); err != nil {
+			if pgErr, ok := err.(pgx.PgError); ok && pgErr.Code == "23503" {
+				if pgErr.ConstraintName == "read_markers_username_fkey" {
+					return nil, user.NotExistsError
+				}
+				return nil, thread.NotFound
+			}
+			return nil, err
+		}
+	}
+
+	threadDetails, err := t.GetThreadDetails(utilities.SlugOrId{ID: threadInfo.ID})
+	if err != nil {
+		return nil, err
+	}
+	if err = t.SetUnread(viewer, threadDetails); err != nil {
+		return nil, err
 	}
 	return threadDetails, nil
 }
 
-func NewThreadUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase) domain.ThreadUsecase {
+func NewThreadUsecase(db *pgx.ConnPool, userUsecase domain.UserUsecase, c *cache.Cache) domain.ThreadUsecase {
 	return &threadUsecase{
 		DB:     db,
 		UUCase: userUsecase,
+		Cache:  c,
 	}
 }
@@ -0,0 +1,15 @@
+package user
+
+import "technopark-dbms/internal/pkg/domain"
+
+// Columns are the user columns Scan expects
+const Columns = "nickname, fullname, about, email, karma, version"
+
+type row interface {
+	Scan(dest ...interface{}) error
+}
+
+// Scan reads Columns into the user
+func Scan(r row, u *domain.User) error {
+	return r.Scan(&u.Nickname, &u.Fullname, &u.About, &u.Email, &u.Karma, &u.Version)
+}
@@ -10,6 +10,7 @@ import (
 	"technopark-dbms/internal/pkg/errors"
 	"technopark-dbms/internal/pkg/user"
 	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
 )
 
 type userHandler struct {
@@ -37,6 +38,11 @@ func (handler *userHandler) userCreateHandler(ctx *fasthttp.RequestCtx) {
 	}
 
 	nickname := ctx.UserValue("nickname").(string)
+	parsedUser.Nickname = nickname
+	if fieldErrors := validation.Struct(parsedUser, validation.Create); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
 
 	createdUser, err, alreadyCreatedUsers := handler.userUsecase.CreateUser(nickname, *parsedUser)
 	if err != nil {
@@ -61,7 +67,7 @@ func (handler *userHandler) userGetProfileHandler(ctx *fasthttp.RequestCtx) {
 		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
 		return
 	}
-	utilities.Resp(ctx, http.StatusOK, foundUser)
+	utilities.RespTagged(ctx, foundUser.Version, foundUser)
 }
 
 func (handler *userHandler) userUpdateProfileHandler(ctx *fasthttp.RequestCtx) {
@@ -73,6 +79,18 @@ func (handler *userHandler) userUpdateProfileHandler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
+	if fieldErrors := validation.Struct(parsedUser, validation.Update); len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+	version, err := utilities.IfMatchVersion(ctx)
+	if err != nil {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(domain.FieldErrorArray{
+			{Field: fasthttp.HeaderIfMatch, Message: err.Error()}}))
+		return
+	}
+	parsedUser.Version = version
+
 	nickname := ctx.UserValue("nickname").(string)
 
 	updatedUser, err := handler.userUsecase.UpdateUser(nickname, *parsedUser)
@@ -81,5 +99,6 @@ func (handler *userHandler) userUpdateProfileHandler(ctx *fasthttp.RequestCtx) {
 		utilities.Resp(ctx, user.CodeFromError(err), errors.JSONErrorMessage(err))
 		return
 	}
+	utilities.SetETag(ctx, updatedUser.Version)
 	utilities.Resp(ctx, fasthttp.StatusOK, updatedUser)
 }
@@ -3,6 +3,7 @@ package user
 import (
 	"errors"
 	"net/http"
+	"technopark-dbms/internal/pkg/utilities"
 )
 
 var (
@@ -19,6 +20,8 @@ func CodeFromError(err error) int {
 		return http.StatusConflict
 	case NotExistsError:
 		return http.StatusNotFound
+	case utilities.PreconditionFailed:
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
@@ -4,21 +4,28 @@ import (
 	"errors"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx"
+	"technopark-dbms/internal/pkg/cache"
 	"technopark-dbms/internal/pkg/domain"
 	"technopark-dbms/internal/pkg/user"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/webhook"
 )
 
 const (
-	createUserQuery      = "insert into users(nickname, fullname, about, email) values ($1, $2, $3, $4) returning nickname, fullname, about, email;"
-	getUserDetailsQuery  = "select nickname, fullname, about, email from users where nickname = $1;"
-	getUsersDetailsQuery = "select nickname, fullname, about, email from users where nickname = $1 or email = $2;"
+	createUserQuery      = "insert into users(nickname, fullname, about, email) values ($1, $2, $3, $4) returning " + user.Columns + ";"
+	getUserDetailsQuery  = "select " + user.Columns + " from users where nickname = $1;"
+	getUsersDetailsQuery = "select " + user.Columns + " from users where nickname = $1 or email = $2;"
 	checkUserExistsQuery = "select nickname from users where nickname = $1 or email = $2;"
+	updateUserQuery      = "update users set fullname = coalesce(nullif($3, ''), fullname), about = coalesce(nullif($4, ''), about), " +
+		"email = coalesce(nullif($5, ''), email) where nickname = $1 and ($2 = 0 or version = $2) returning " + user.Columns + ";"
+	checkUserVersionQuery = "select " + user.Columns + " from users where nickname = $1 and ($2 = 0 or version = $2);"
 )
 
 var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 
 type userUsecase struct {
-	DB *pgx.ConnPool
+	DB    *pgx.ConnPool
+	Cache *cache.Cache
 }
 
 func (u *userUsecase) GetProfiles(nickname, email string) (domain.UserArray, error) {
@@ -33,10 +40,7 @@ func (u *userUsecase) GetProfiles(nickname, email string) (domain.UserArray, err
 	resUsers := make(domain.UserArray, 0)
 	for rows.Next() {
 		var currentUser domain.User
-		if err = rows.Scan(&currentUser.Nickname,
-			&currentUser.Fullname,
-			&currentUser.About,
-			&currentUser.Email); err != nil {
+		if err = user.Scan(rows, &currentUser); err != nil {
 			return nil, errors.New("row scan error")
 		}
 		resUsers = append(resUsers, currentUser)
@@ -47,9 +51,10 @@ func (u *userUsecase) GetProfiles(nickname, email string) (domain.UserArray, err
 	return resUsers, nil
 }
 
-func NewUserUsecase(db *pgx.ConnPool) domain.UserUsecase {
+func NewUserUsecase(db *pgx.ConnPool, c *cache.Cache) domain.UserUsecase {
 	return &userUsecase{
-		DB: db,
+		DB:    db,
+		Cache: c,
 	}
 }
 
@@ -61,71 +66,86 @@ func (u *userUsecase) CreateUser(nickname string, createData domain.User) (*doma
 		return nil, err, nil
 	}
 
+	tx, err := u.DB.Begin()
+	if err != nil {
+		return nil, err, nil
+	}
+	defer tx.Rollback()
+
 	query := createUserQuery
 	createdUser := &domain.User{}
-	err = u.DB.QueryRow(query, nickname, createData.Fullname, createData.About, createData.Email).
-		Scan(&createdUser.Nickname, &createdUser.Fullname, &createdUser.About, &createdUser.Email)
+	err = user.Scan(tx.QueryRow(query, nickname, createData.Fullname, createData.About, createData.Email), createdUser)
 	if err != nil {
 		return nil, err, nil
 	}
+	if err = webhook.Enqueue(tx, webhook.UserCreated, createdUser); err != nil {
+		return nil, err, nil
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err, nil
+	}
 	return createdUser, nil, nil
 }
 
 func (u *userUsecase) GetProfile(nickname string) (*domain.User, error) {
-	query := getUserDetailsQuery
 	foundUser := &domain.User{}
-	err := u.DB.QueryRow(query, nickname).
-		Scan(&foundUser.Nickname, &foundUser.Fullname, &foundUser.About, &foundUser.Email)
+	if u.Cache.Load(cache.UserKey(nickname), foundUser) {
+		return foundUser, nil
+	}
+	query := getUserDetailsQuery
+	err := user.Scan(u.DB.QueryRow(query, nickname), foundUser)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, user.NotExistsError
 		}
 		return nil, err
 	}
+	u.Cache.Store(cache.UserKey(nickname), foundUser)
 	return foundUser, err
 }
-func (u *userUsecase) UpdateUser(nickname string, userUpdate domain.User) (*domain.User, error) {
-	if userUpdate.Email == "" && userUpdate.About == "" && userUpdate.Fullname == "" {
-		return u.GetProfile(nickname)
-	}
-
-	foundUser, err := u.GetProfile(nickname)
-	if err != nil {
-		return nil, err
-	}
 
+// UpdateUser applies non empty fields of the update.
+// Non zero Version of the update must be the current one, so concurrent edits are not overwritten.
+func (u *userUsecase) UpdateUser(nickname string, userUpdate domain.User) (*domain.User, error) {
 	if userUpdate.Email != "" {
 		emailConflict, err := u.UserExists("", userUpdate.Email)
 		if err != nil {
 			return nil, err
 		}
 		if emailConflict {
-			return nil, user.UpdateConflict
+			return nil, u.missingOr(nickname, user.UpdateConflict)
 		}
 	}
 
-	if userUpdate.Email == "" {
-		userUpdate.Email = foundUser.Email
-	} else {
-		foundUser.Email = userUpdate.Email
+	// an empty update changes nothing, so it only checks the version
+	query := checkUserVersionQuery
+	args := []interface{}{nickname, userUpdate.Version}
+	if userUpdate.Email != "" || userUpdate.About != "" || userUpdate.Fullname != "" {
+		query = updateUserQuery
+		args = append(args, userUpdate.Fullname, userUpdate.About, userUpdate.Email)
 	}
-	if userUpdate.About == "" {
-		userUpdate.About = foundUser.About
-	} else {
-		foundUser.About = userUpdate.About
+	updatedUser := &domain.User{}
+	err := user.Scan(u.DB.QueryRow(query, args...), updatedUser)
+	u.Cache.Invalidate(cache.UserKey(nickname))
+	if err == pgx.ErrNoRows {
+		return nil, u.missingOr(nickname, utilities.PreconditionFailed)
 	}
-	if userUpdate.Fullname == "" {
-		userUpdate.Fullname = foundUser.Fullname
-	} else {
-		foundUser.Fullname = userUpdate.Fullname
-	}
-
-	query := "update users set fullname = $1, about = $2, email = $3 where nickname = $4"
-	_, err = u.DB.Exec(query, userUpdate.Fullname, userUpdate.About, userUpdate.Email, nickname)
 	if err != nil {
 		return nil, err
 	}
-	return foundUser, nil
+	return updatedUser, nil
+}
+
+// missingOr is NotExistsError for a missing user and err otherwise
+func (u *userUsecase) missingOr(nickname string, err error) error {
+	exists, existsErr := u.UserExists(nickname, "")
+	if existsErr != nil {
+		return existsErr
+	}
+	if !exists {
+		return user.NotExistsError
+	}
+	return err
 }
 
 func (u *userUsecase) UserExists(nickname string, email string) (bool, error) {
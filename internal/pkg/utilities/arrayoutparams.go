@@ -1,14 +1,25 @@
 package utilities
 
 import (
+	"errors"
 	"github.com/valyala/fasthttp"
 )
 
+var (
+	LimitError    = errors.New("limit must be a positive integer")
+	MaxDepthError = errors.New("max_depth must be a non negative integer")
+)
+
 type ArrayOutParams struct {
-	Limit int32
-	Since string
-	Desc  bool
-	Sort  string
+	Limit    int32
+	Since    string
+	Desc     bool
+	Sort     string
+	OrderBy  string
+	Window   string
+	Tag      string
+	Cursor   string
+	MaxDepth int32
 }
 
 func NewArrayOutParams(queryArgs *fasthttp.Args) (*ArrayOutParams, error) {
@@ -17,7 +28,7 @@ func NewArrayOutParams(queryArgs *fasthttp.Args) (*ArrayOutParams, error) {
 	if queryArgs.Has("limit") {
 		parsedLimit, err := queryArgs.GetUint("limit")
 		if err != nil {
-			return nil, err
+			return nil, LimitError
 		}
 		res.Limit = int32(parsedLimit)
 	} else {
@@ -33,5 +44,30 @@ func NewArrayOutParams(queryArgs *fasthttp.Args) (*ArrayOutParams, error) {
 	if queryArgs.Has("sort") {
 		res.Sort = string(queryArgs.Peek("sort"))
 	}
+
+	if queryArgs.Has("order_by") {
+		res.OrderBy = string(queryArgs.Peek("order_by"))
+	}
+
+	if queryArgs.Has("window") {
+		res.Window = string(queryArgs.Peek("window"))
+	}
+
+	if queryArgs.Has("tag") {
+		res.Tag = string(queryArgs.Peek("tag"))
+	}
+
+	if queryArgs.Has("cursor") {
+		res.Cursor = string(queryArgs.Peek("cursor"))
+	}
+
+	// zero depth means the whole tree
+	if queryArgs.Has("max_depth") {
+		parsedDepth, err := queryArgs.GetUint("max_depth")
+		if err != nil {
+			return nil, MaxDepthError
+		}
+		res.MaxDepth = int32(parsedDepth)
+	}
 	return res, nil
 }
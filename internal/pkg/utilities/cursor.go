@@ -0,0 +1,111 @@
+package utilities
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strings"
+)
+
+const cursorSecretEnv = "DBMS_CURSOR_SECRET"
+
+var CursorError = errors.New("wrong cursor")
+
+// cursorSecret signs cursors, without the env variable cursors are valid until the server restarts
+// and only on the instance that issued them
+var cursorSecret = loadCursorSecret()
+
+func loadCursorSecret() []byte {
+	if secret := os.Getenv(cursorSecretEnv); secret != "" {
+		return []byte(secret)
+	}
+	log.Warn(cursorSecretEnv + " is not set, cursors are signed with a random key and break on restart and across instances")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.WithError(err).Fatal("cursor secret generation error")
+	}
+	return secret
+}
+
+// Cursor points at the boundary row of a listing page.
+// Key is the full sort key of the row with the tie-breaker as its last value,
+// Scope binds the cursor to the listing and sort it was issued for.
+type Cursor struct {
+	Scope    string   `json:"s"`
+	Key      []string `json:"k"`
+	Desc     bool     `json:"d,omitempty"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signCursor(encodedPayload))
+}
+
+func DecodeCursor(token string, scope string, keyLen int) (*Cursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, CursorError
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, signCursor(parts[0])) {
+		return nil, CursorError
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, CursorError
+	}
+	c := &Cursor{}
+	if err = json.Unmarshal(payload, c); err != nil {
+		return nil, CursorError
+	}
+	if c.Scope != scope || len(c.Key) != keyLen {
+		return nil, CursorError
+	}
+	return c, nil
+}
+
+func signCursor(payload string) []byte {
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// Page holds the cursors of the pages around the returned one, empty cursor means there is no such page
+type Page struct {
+	Next string
+	Prev string
+}
+
+// NewPage builds cursors for a fetched page. Rows of a backward page must be already
+// put back into the listing order, so first and last keys are always taken in that order.
+func NewPage(scope string, desc bool, current *Cursor, count int, limit int32, firstKey, lastKey []string) *Page {
+	page := &Page{}
+	if count == 0 {
+		return page
+	}
+	backward := current != nil && current.Backward
+	hasMore := count == int(limit)
+
+	if !backward && hasMore || backward {
+		page.Next = Cursor{Scope: scope, Key: lastKey, Desc: desc}.Encode()
+	}
+	if backward && hasMore || !backward && current != nil {
+		page.Prev = Cursor{Scope: scope, Key: firstKey, Desc: desc, Backward: true}.Encode()
+	}
+	return page
+}
+
+// ParamsCursor decodes the cursor of the listing params, no cursor gives nil
+func ParamsCursor(params ArrayOutParams, scope string, keyLen int) (*Cursor, error) {
+	if params.Cursor == "" {
+		return nil, nil
+	}
+	return DecodeCursor(params.Cursor, scope, keyLen)
+}
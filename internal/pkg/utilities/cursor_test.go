@@ -0,0 +1,96 @@
+package utilities
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	valid := Cursor{Scope: "posts", Key: []string{"2021-01-01", "42"}, Desc: true}.Encode()
+	payload := strings.Split(valid, ".")[0]
+	tampered := Cursor{Scope: "posts", Key: []string{"2021-01-01", "43"}, Desc: true}.Encode()
+	forged := strings.Split(tampered, ".")[0] + "." + strings.Split(valid, ".")[1]
+
+	tests := []struct {
+		name    string
+		token   string
+		scope   string
+		keyLen  int
+		want    *Cursor
+		wantErr bool
+	}{
+		{"valid", valid, "posts", 2, &Cursor{Scope: "posts", Key: []string{"2021-01-01", "42"}, Desc: true}, false},
+		{"other scope", valid, "threads", 2, nil, true},
+		{"other key length", valid, "posts", 1, nil, true},
+		{"forged payload", forged, "posts", 2, nil, true},
+		{"no signature", payload, "posts", 2, nil, true},
+		{"malformed signature", payload + ".!!", "posts", 2, nil, true},
+		{"extra part", valid + ".x", "posts", 2, nil, true},
+		{"empty", "", "posts", 2, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DecodeCursor(test.token, test.scope, test.keyLen)
+			if test.wantErr {
+				if err != CursorError {
+					t.Errorf("DecodeCursor() error = %v, want %v", err, CursorError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeCursor() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("DecodeCursor() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	first, last := []string{"1"}, []string{"5"}
+	forward := &Cursor{Scope: "s", Key: []string{"0"}}
+	backward := &Cursor{Scope: "s", Key: []string{"6"}, Backward: true}
+
+	tests := []struct {
+		name     string
+		current  *Cursor
+		count    int
+		limit    int32
+		wantNext *Cursor
+		wantPrev *Cursor
+	}{
+		{"empty page", nil, 0, 5, nil, nil},
+		{"only page", nil, 3, 5, nil, nil},
+		{"first of many", nil, 5, 5, &Cursor{Scope: "s", Key: last}, nil},
+		{"middle", forward, 5, 5, &Cursor{Scope: "s", Key: last}, &Cursor{Scope: "s", Key: first, Backward: true}},
+		{"last", forward, 3, 5, nil, &Cursor{Scope: "s", Key: first, Backward: true}},
+		{"backward middle", backward, 5, 5, &Cursor{Scope: "s", Key: last}, &Cursor{Scope: "s", Key: first, Backward: true}},
+		{"backward to the start", backward, 3, 5, &Cursor{Scope: "s", Key: last}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			page := NewPage("s", false, test.current, test.count, test.limit, first, last)
+			checkPageCursor(t, "Next", page.Next, test.wantNext)
+			checkPageCursor(t, "Prev", page.Prev, test.wantPrev)
+		})
+	}
+}
+
+func checkPageCursor(t *testing.T, name string, token string, want *Cursor) {
+	t.Helper()
+	if want == nil {
+		if token != "" {
+			t.Errorf("%s = %q, want no cursor", name, token)
+		}
+		return
+	}
+	got, err := DecodeCursor(token, want.Scope, len(want.Key))
+	if err != nil {
+		t.Fatalf("%s cursor %q does not decode: %v", name, token, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s = %+v, want %+v", name, got, want)
+	}
+}
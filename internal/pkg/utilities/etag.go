@@ -0,0 +1,77 @@
+package utilities
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"github.com/mailru/easyjson"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"strings"
+)
+
+var (
+	PreconditionFailed = errors.New("resource was modified")
+	ETagError          = errors.New("wrong entity tag")
+)
+
+// ETag is the entity tag of a resource version
+func ETag(version int32) string {
+	return "\"" + strconv.FormatInt(int64(version), 10) + "\""
+}
+
+// SetETag sets the ETag header of a resource version
+func SetETag(ctx *fasthttp.RequestCtx, version int32) {
+	ctx.Response.Header.Set(fasthttp.HeaderETag, ETag(version))
+}
+
+// BodyETag is the entity tag of a response body of a resource version.
+// Counters kept by triggers change the body without a new version, so the body hash is a part of the tag,
+// the version in front keeps the tag usable with If-Match.
+func BodyETag(version int32, body []byte) string {
+	sum := sha256.Sum256(body)
+	return "\"" + strconv.FormatInt(int64(version), 10) + "-" + base64.RawURLEncoding.EncodeToString(sum[:12]) + "\""
+}
+
+// RespTagged writes the resource with the ETag of its body and answers 304 if If-None-Match has that tag
+func RespTagged(ctx *fasthttp.RequestCtx, version int32, v easyjson.Marshaler) {
+	body, err := easyjson.Marshal(v)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		return
+	}
+	etag := BodyETag(version, body)
+	ctx.Response.Header.Set(fasthttp.HeaderETag, etag)
+	ifNoneMatch := string(ctx.Request.Header.Peek(fasthttp.HeaderIfNoneMatch))
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			ctx.SetStatusCode(fasthttp.StatusNotModified)
+			return
+		}
+	}
+	ctx.SetBody(body)
+	ctx.SetContentType("application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+}
+
+// IfMatchVersion returns the version If-Match requires, zero if any version fits.
+// Tags of response bodies are matched by the version in front of the body hash.
+func IfMatchVersion(ctx *fasthttp.RequestCtx) (int32, error) {
+	ifMatch := strings.TrimSpace(string(ctx.Request.Header.Peek(fasthttp.HeaderIfMatch)))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	if len(ifMatch) < 2 || ifMatch[0] != '"' || ifMatch[len(ifMatch)-1] != '"' {
+		return 0, ETagError
+	}
+	tag := ifMatch[1 : len(ifMatch)-1]
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		tag = tag[:i]
+	}
+	version, err := strconv.ParseInt(tag, 10, 32)
+	if err != nil || version <= 0 {
+		return 0, ETagError
+	}
+	return int32(version), nil
+}
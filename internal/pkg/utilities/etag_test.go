@@ -0,0 +1,42 @@
+package utilities
+
+import (
+	"github.com/valyala/fasthttp"
+	"testing"
+)
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int32
+		wantErr bool
+	}{
+		{"absent", "", 0, false},
+		{"any", "*", 0, false},
+		{"version", ETag(3), 3, false},
+		{"body tag", BodyETag(4, []byte("{}")), 4, false},
+		{"unquoted", "3", 0, true},
+		{"not a version", "\"x-1\"", 0, true},
+		{"zero", "\"0\"", 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.Set(fasthttp.HeaderIfMatch, test.ifMatch)
+			got, err := IfMatchVersion(ctx)
+			if (err != nil) != test.wantErr || got != test.want {
+				t.Errorf("IfMatchVersion() = %d, %v, want %d, error %t", got, err, test.want, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestBodyETag(t *testing.T) {
+	if BodyETag(1, []byte(`{"votes":1}`)) == BodyETag(1, []byte(`{"votes":2}`)) {
+		t.Error("BodyETag() is the same for bodies of one version")
+	}
+	if BodyETag(1, []byte("{}")) == BodyETag(2, []byte("{}")) {
+		t.Error("BodyETag() is the same for versions of one body")
+	}
+}
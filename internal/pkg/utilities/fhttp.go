@@ -1,12 +1,54 @@
 package utilities
 
 import (
+	"fmt"
 	"github.com/mailru/easyjson"
 	"github.com/valyala/fasthttp"
+	"strings"
 )
 
+// ViewerHeader carries the nickname of the user the response is personalized for
+const ViewerHeader = "X-Nickname"
+
+// Viewer returns the nickname of the requesting user, empty for anonymous requests
+func Viewer(ctx *fasthttp.RequestCtx) string {
+	return strings.TrimSpace(string(ctx.Request.Header.Peek(ViewerHeader)))
+}
+
+// VaryOnViewer tells caches that the response is personalized, so one user never gets a copy cached for another
+func VaryOnViewer(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set(fasthttp.HeaderVary, ViewerHeader)
+}
+
+// RenderHTML tells whether the request asked for messages rendered into HTML with render=html
+func RenderHTML(ctx *fasthttp.RequestCtx) bool {
+	return string(ctx.QueryArgs().Peek("render")) == "html"
+}
+
 func Resp(ctx *fasthttp.RequestCtx, code int, v easyjson.Marshaler) {
 	_, _ = easyjson.MarshalToWriter(v, ctx)
 	ctx.SetContentType("application/json")
 	ctx.SetStatusCode(code)
 }
+
+// SetPageLinks sets Link header pointing at the pages around the returned one
+func SetPageLinks(ctx *fasthttp.RequestCtx, page *Page) {
+	if page == nil {
+		return
+	}
+	links := make([]string, 0, 2)
+	for _, link := range []struct{ rel, cursor string }{{"next", page.Next}, {"prev", page.Prev}} {
+		if link.cursor == "" {
+			continue
+		}
+		args := fasthttp.AcquireArgs()
+		ctx.QueryArgs().CopyTo(args)
+		args.Del("since")
+		args.Set("cursor", link.cursor)
+		links = append(links, fmt.Sprintf("<%s?%s>; rel=\"%s\"", ctx.Path(), args.QueryString(), link.rel))
+		fasthttp.ReleaseArgs(args)
+	}
+	if len(links) != 0 {
+		ctx.Response.Header.Set("Link", strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,62 @@
+package validation
+
+import (
+	"fmt"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/utilities"
+	"time"
+)
+
+// Since formats of listing params
+const (
+	SinceDate = "date"
+	SinceID   = "id"
+	SinceAny  = ""
+)
+
+// ListParams parses listing query args and checks them with Params
+func ListParams(queryArgs *fasthttp.Args, since string, sorts ...string) (*utilities.ArrayOutParams, domain.FieldErrorArray) {
+	params, err := utilities.NewArrayOutParams(queryArgs)
+	if err == utilities.MaxDepthError {
+		return nil, domain.FieldErrorArray{{Field: "max_depth", Message: "must be a non negative integer"}}
+	} else if err != nil {
+		return nil, domain.FieldErrorArray{{Field: "limit", Message: "must be a positive integer"}}
+	}
+	return params, Params(*params, since, sorts...)
+}
+
+// Params checks listing limit bounds, the sort name when sorts are given and the since format
+func Params(params utilities.ArrayOutParams, since string, sorts ...string) domain.FieldErrorArray {
+	res := make(domain.FieldErrorArray, 0)
+	if params.Limit < 1 || params.Limit > constants.MaxLimit {
+		res = append(res, domain.FieldError{Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", constants.MaxLimit)})
+	}
+
+	if len(sorts) != 0 && params.Sort != "" {
+		known := false
+		for _, sort := range sorts {
+			known = known || sort == params.Sort
+		}
+		if !known {
+			res = append(res, domain.FieldError{Field: "sort", Message: errors.WrongSortType.Error()})
+		}
+	}
+
+	if params.Since != "" {
+		var err error
+		switch since {
+		case SinceDate:
+			_, err = time.Parse(constants.TimeLayout, params.Since)
+		case SinceID:
+			_, err = strconv.ParseInt(params.Since, 10, 64)
+		}
+		if err != nil {
+			res = append(res, domain.FieldError{Field: "since", Message: "must be a valid " + since})
+		}
+	}
+	return res
+}
@@ -0,0 +1,142 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"technopark-dbms/internal/pkg/domain"
+	"unicode/utf8"
+)
+
+// Groups select rules written as rule@group, rules without a group are always checked
+const (
+	Create = "create"
+	Update = "update"
+)
+
+var patterns = map[string]struct {
+	re      *regexp.Regexp
+	message string
+}{
+	"nickname": {regexp.MustCompile(`^[A-Za-z0-9_.]+$`), "must contain only latin letters, digits, dots and underscores"},
+	"slug":     {regexp.MustCompile(`^[A-Za-z0-9_-]*[A-Za-z_-][A-Za-z0-9_-]*$`), "must contain latin letters, digits, dashes and underscores and must not be a number"},
+	"email":    {regexp.MustCompile(`^[^@\s]+@[^@\s]+$`), "must be a valid email"},
+	"url":      {regexp.MustCompile(`^https?://[^\s/]+\S*$`), "must be an http or https url"},
+}
+
+// Struct checks the validate tags of a struct or of every element of a slice of structs
+func Struct(v interface{}, group string) domain.FieldErrorArray {
+	res := make(domain.FieldErrorArray, 0)
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			res = append(res, checkStruct(reflect.Indirect(value.Index(i)), fmt.Sprintf("[%d].", i), group)...)
+		}
+		return res
+	}
+	return append(res, checkStruct(value, "", group)...)
+}
+
+// Var checks a single value such as a path or a query param against comma separated rules
+func Var(field string, value interface{}, rules string) domain.FieldErrorArray {
+	res := make(domain.FieldErrorArray, 0)
+	for _, r := range strings.Split(rules, ",") {
+		if message := check(reflect.ValueOf(value), r); message != "" {
+			res = append(res, domain.FieldError{Field: field, Message: message})
+			break
+		}
+	}
+	return res
+}
+
+func checkStruct(value reflect.Value, prefix string, group string) domain.FieldErrorArray {
+	res := make(domain.FieldErrorArray, 0)
+	if value.Kind() != reflect.Struct {
+		return res
+	}
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		rules, ok := field.Tag.Lookup("validate")
+		if !ok {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		for _, r := range strings.Split(rules, ",") {
+			if at := strings.Index(r, "@"); at != -1 {
+				if r[at+1:] != group {
+					continue
+				}
+				r = r[:at]
+			}
+			if message := check(value.Field(i), r); message != "" {
+				res = append(res, domain.FieldError{Field: prefix + name, Message: message})
+				break
+			}
+		}
+	}
+	return res
+}
+
+// check returns the violation message of the rule or empty string for a valid value.
+// Everything but required passes zero values, so optional fields are checked only when set.
+func check(value reflect.Value, r string) string {
+	name, arg := r, ""
+	if eq := strings.Index(r, "="); eq != -1 {
+		name, arg = r[:eq], r[eq+1:]
+	}
+	if name == "required" {
+		if isZero(value) {
+			return "is required"
+		}
+		return ""
+	}
+	if isZero(value) {
+		return ""
+	}
+	value = reflect.Indirect(value)
+
+	switch name {
+	case "max":
+		limit, _ := strconv.Atoi(arg)
+		if length(value) > limit {
+			return fmt.Sprintf("must be at most %d long", limit)
+		}
+	case "oneof":
+		current := fmt.Sprint(value.Interface())
+		for _, allowed := range strings.Fields(arg) {
+			if current == allowed {
+				return ""
+			}
+		}
+		return "must be one of: " + strings.Join(strings.Fields(arg), ", ")
+	default:
+		pattern, ok := patterns[name]
+		if ok && value.Kind() == reflect.String && !pattern.re.MatchString(value.String()) {
+			return pattern.message
+		}
+	}
+	return ""
+}
+
+func isZero(value reflect.Value) bool {
+	if value.Kind() == reflect.String {
+		return strings.TrimSpace(value.String()) == ""
+	}
+	return value.IsZero()
+}
+
+func length(value reflect.Value) int {
+	if value.Kind() == reflect.String {
+		return utf8.RuneCountInString(value.String())
+	}
+	if value.Kind() == reflect.Slice {
+		return value.Len()
+	}
+	return 0
+}
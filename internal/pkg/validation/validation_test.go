@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"reflect"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/utilities"
+	"testing"
+)
+
+type testEntity struct {
+	Nickname string   `json:"nickname" validate:"required@create,nickname"`
+	About    string   `json:"about" validate:"max=5"`
+	Email    string   `json:"email,omitempty" validate:"email"`
+	Voice    int32    `json:"voice" validate:"oneof=-1 1"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Ignored  string   `json:"ignored"`
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		group string
+		want  domain.FieldErrorArray
+	}{
+		{"valid", &testEntity{Nickname: "john.doe", About: "hi", Email: "a@b.c", Voice: 1}, Create, domain.FieldErrorArray{}},
+		{"required in group", &testEntity{}, Create, domain.FieldErrorArray{{Field: "nickname", Message: "is required"}}},
+		{"required out of group", &testEntity{}, Update, domain.FieldErrorArray{}},
+		{"blank is missing", &testEntity{Nickname: "  "}, Create, domain.FieldErrorArray{{Field: "nickname", Message: "is required"}}},
+		{"pattern", &testEntity{Nickname: "john doe"}, Update,
+			domain.FieldErrorArray{{Field: "nickname", Message: patterns["nickname"].message}}},
+		{"max counts runes", &testEntity{About: "привет"}, Update,
+			domain.FieldErrorArray{{Field: "about", Message: "must be at most 5 long"}}},
+		{"max of slice", &testEntity{Tags: []string{"a", "b", "c"}}, Update,
+			domain.FieldErrorArray{{Field: "tags", Message: "must be at most 2 long"}}},
+		{"oneof", &testEntity{Voice: 2}, Update,
+			domain.FieldErrorArray{{Field: "voice", Message: "must be one of: -1, 1"}}},
+		{"json name without options", &testEntity{Email: "nope"}, Update,
+			domain.FieldErrorArray{{Field: "email", Message: patterns["email"].message}}},
+		{"every field", &testEntity{Nickname: "a b", Voice: 3}, Create, domain.FieldErrorArray{
+			{Field: "nickname", Message: patterns["nickname"].message},
+			{Field: "voice", Message: "must be one of: -1, 1"}}},
+		{"slice elements", []testEntity{{Nickname: "ok"}, {}}, Create,
+			domain.FieldErrorArray{{Field: "[1].nickname", Message: "is required"}}},
+		{"not a struct", "text", Create, domain.FieldErrorArray{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Struct(test.value, test.group); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Struct() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVar(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		rules string
+		want  domain.FieldErrorArray
+	}{
+		{"optional empty", "", "nickname", domain.FieldErrorArray{}},
+		{"required empty", "", "required,nickname", domain.FieldErrorArray{{Field: "f", Message: "is required"}}},
+		{"first violation only", "a b c d e f", "max=3,nickname", domain.FieldErrorArray{{Field: "f", Message: "must be at most 3 long"}}},
+		{"slug", "123", "slug", domain.FieldErrorArray{{Field: "f", Message: patterns["slug"].message}}},
+		{"slug with letters", "thread-1", "slug", domain.FieldErrorArray{}},
+		{"url", "ftp://host", "url", domain.FieldErrorArray{{Field: "f", Message: patterns["url"].message}}},
+		{"https url", "https://host/hook", "url", domain.FieldErrorArray{}},
+		{"oneof word", "week", "oneof=day week", domain.FieldErrorArray{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Var("f", test.value, test.rules); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Var() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params utilities.ArrayOutParams
+		since  string
+		sorts  []string
+		want   []string
+	}{
+		{"valid", utilities.ArrayOutParams{Limit: 10}, SinceAny, nil, []string{}},
+		{"zero limit", utilities.ArrayOutParams{Limit: 0}, SinceAny, nil, []string{"limit"}},
+		{"limit over max", utilities.ArrayOutParams{Limit: 10001}, SinceAny, nil, []string{"limit"}},
+		{"known sort", utilities.ArrayOutParams{Limit: 1, Sort: "flat"}, SinceAny, []string{"flat", "tree"}, []string{}},
+		{"unknown sort", utilities.ArrayOutParams{Limit: 1, Sort: "hot"}, SinceAny, []string{"flat", "tree"}, []string{"sort"}},
+		{"sort without sorts", utilities.ArrayOutParams{Limit: 1, Sort: "hot"}, SinceAny, nil, []string{}},
+		{"since id", utilities.ArrayOutParams{Limit: 1, Since: "42"}, SinceID, nil, []string{}},
+		{"since not an id", utilities.ArrayOutParams{Limit: 1, Since: "x"}, SinceID, nil, []string{"since"}},
+		{"since date", utilities.ArrayOutParams{Limit: 1, Since: "2021-01-02T03:04:05.000Z"}, SinceDate, nil, []string{}},
+		{"since not a date", utilities.ArrayOutParams{Limit: 1, Since: "42"}, SinceDate, nil, []string{"since"}},
+		{"since anything", utilities.ArrayOutParams{Limit: 1, Since: "x"}, SinceAny, nil, []string{}},
+		{"every field", utilities.ArrayOutParams{Sort: "x", Since: "x"}, SinceID, []string{"flat"}, []string{"limit", "sort", "since"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields := make([]string, 0)
+			for _, fieldError := range Params(test.params, test.since, test.sorts...) {
+				fields = append(fields, fieldError.Field)
+			}
+			if !reflect.DeepEqual(fields, test.want) {
+				t.Errorf("Params() fields = %q, want %q", fields, test.want)
+			}
+		})
+	}
+}
+
+func TestStructPointer(t *testing.T) {
+	voice := func(v int32) *int32 { return &v }
+	tests := []struct {
+		name  string
+		value domain.Vote
+		want  domain.FieldErrorArray
+	}{
+		{"omitted", domain.Vote{Nickname: "a"}, domain.FieldErrorArray{{Field: "voice", Message: "is required"}}},
+		{"zero", domain.Vote{Nickname: "a", Voice: voice(0)}, domain.FieldErrorArray{}},
+		{"minus one", domain.Vote{Nickname: "a", Voice: voice(-1)}, domain.FieldErrorArray{}},
+		{"out of range", domain.Vote{Nickname: "a", Voice: voice(2)},
+			domain.FieldErrorArray{{Field: "voice", Message: "must be one of: -1, 0, 1"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Struct(&test.value, Create); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Struct() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
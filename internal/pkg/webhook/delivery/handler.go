@@ -0,0 +1,143 @@
+package delivery
+
+import (
+	"github.com/fasthttp/router"
+	"github.com/mailru/easyjson"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/errors"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/validation"
+	"technopark-dbms/internal/pkg/webhook"
+)
+
+type webhookHandler struct {
+	webhookUsecase domain.WebhookUsecase
+}
+
+func NewWebhookHandler(r *router.Router, wu domain.WebhookUsecase) {
+	h := webhookHandler{
+		webhookUsecase: wu,
+	}
+	s := r.Group("/api/webhooks")
+
+	s.POST("", h.webhookCreateHandler)
+	s.GET("", h.webhookGetAllHandler)
+	s.DELETE("/{id:[0-9]+}", h.webhookDeleteHandler)
+	s.GET("/{id:[0-9]+}/deliveries", h.webhookGetDeliveriesHandler)
+	s.POST("/deliveries/{id:[0-9]+}/replay", h.deliveryReplayHandler)
+}
+
+func (handler *webhookHandler) webhookCreateHandler(ctx *fasthttp.RequestCtx) {
+	parsedWebhook := &domain.Webhook{}
+	err := easyjson.Unmarshal(ctx.PostBody(), parsedWebhook)
+	if err != nil {
+		log.WithError(err).Error(errors.JSONUnmarshallError)
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONDecodeErrorMessage)
+		return
+	}
+
+	fieldErrors := validation.Struct(parsedWebhook, validation.Create)
+	for _, event := range parsedWebhook.Events {
+		fieldErrors = append(fieldErrors, validation.Var("events", event, "required,oneof="+webhook.EventTypes)...)
+	}
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	createdWebhook, err := handler.webhookUsecase.CreateWebhook(*parsedWebhook)
+	if err != nil {
+		log.WithError(err).Error("webhook create error")
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusCreated, createdWebhook)
+}
+
+func (handler *webhookHandler) webhookGetAllHandler(ctx *fasthttp.RequestCtx) {
+	webhooks, err := handler.webhookUsecase.GetWebhooks()
+	if err != nil {
+		log.WithError(err).Error("webhook get all error")
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, webhooks)
+}
+
+func (handler *webhookHandler) webhookDeleteHandler(ctx *fasthttp.RequestCtx) {
+	webhookId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+
+	err = handler.webhookUsecase.DeleteWebhook(webhookId)
+	if err != nil {
+		log.WithError(err).Error("webhook delete error")
+		if err == webhook.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+func (handler *webhookHandler) webhookGetDeliveriesHandler(ctx *fasthttp.RequestCtx) {
+	webhookId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+	params, fieldErrors := validation.ListParams(ctx.QueryArgs(), validation.SinceID)
+	status := string(ctx.QueryArgs().Peek("status"))
+	fieldErrors = append(fieldErrors, validation.Var("status", status,
+		"oneof="+webhook.StatusPending+" "+webhook.StatusDelivered+" "+webhook.StatusDead)...)
+	if len(fieldErrors) != 0 {
+		utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONValidationMessage(fieldErrors))
+		return
+	}
+
+	deliveries, page, err := handler.webhookUsecase.GetDeliveries(webhookId, status, *params)
+	if err != nil {
+		log.WithError(err).Error("webhook get deliveries error")
+		if err == webhook.NotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		} else if err == utilities.CursorError {
+			utilities.Resp(ctx, fasthttp.StatusBadRequest, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.SetPageLinks(ctx, page)
+	utilities.Resp(ctx, fasthttp.StatusOK, deliveries)
+}
+
+func (handler *webhookHandler) deliveryReplayHandler(ctx *fasthttp.RequestCtx) {
+	deliveryId, err := strconv.ParseInt(ctx.UserValue("id").(string), 10, 64)
+	if err != nil {
+		log.WithError(err).Error(errors.URLParamsError)
+		utilities.Resp(ctx, errors.CodeFromDeliveryError(errors.URLParamsError), errors.JSONURLParamsErrorMessage)
+		return
+	}
+
+	replayedDelivery, err := handler.webhookUsecase.ReplayDelivery(deliveryId)
+	if err != nil {
+		log.WithError(err).Error("webhook delivery replay error")
+		if err == webhook.DeliveryNotFound {
+			utilities.Resp(ctx, fasthttp.StatusNotFound, errors.JSONErrorMessage(err))
+			return
+		}
+		utilities.Resp(ctx, fasthttp.StatusInternalServerError, errors.JSONErrorMessage(err))
+		return
+	}
+	utilities.Resp(ctx, fasthttp.StatusOK, replayedDelivery)
+}
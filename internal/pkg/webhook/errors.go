@@ -0,0 +1,8 @@
+package webhook
+
+import "errors"
+
+var (
+	NotFound         = errors.New("webhook not found")
+	DeliveryNotFound = errors.New("webhook delivery not found")
+)
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/jackc/pgx"
+	"github.com/mailru/easyjson"
+	"strconv"
+)
+
+// Event types webhooks subscribe to
+const (
+	UserCreated   = "user.created"
+	ForumCreated  = "forum.created"
+	ThreadCreated = "thread.created"
+	PostsCreated  = "posts.created"
+	PostEdited    = "post.edited"
+	VoteCast      = "vote.cast"
+)
+
+// EventTypes are the events webhooks accept, in the format of the oneof validation rule
+const EventTypes = UserCreated + " " + ForumCreated + " " + ThreadCreated + " " + PostsCreated + " " + PostEdited + " " + VoteCast
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+)
+
+// Headers of delivery requests
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// enqueueQuery writes nothing when no active webhook subscribes to the event type
+const enqueueQuery = `insert into outbox(type, payload)
+select $1::text, $2::text::jsonb
+where exists(select 1 from webhooks where active and $1::text = any (events));`
+
+// Enqueue writes the event into the outbox within the transaction of the change it describes,
+// so the event is delivered if and only if the change is committed
+func Enqueue(tx *pgx.Tx, eventType string, payload easyjson.Marshaler) error {
+	data, err := easyjson.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(enqueueQuery, eventType, string(data))
+	return err
+}
+
+// Sign is the hex HMAC-SHA256 of the timestamp and the body joined with a dot,
+// receivers recompute it with the webhook secret and reject stale timestamps
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,33 @@
+package webhook
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{"known signature", "secret", 1600000000, `{"a":1}`,
+			"sha256=4e107d82910257d43758070322323c95b92af39939824d6610e2c9809a43b8d5"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Sign(test.secret, test.timestamp, []byte(test.body)); got != test.want {
+				t.Errorf("Sign() = %q, want %q", got, test.want)
+			}
+		})
+	}
+	base := Sign("secret", 1600000000, []byte(`{"a":1}`))
+	if Sign("other", 1600000000, []byte(`{"a":1}`)) == base {
+		t.Error("signature does not depend on the secret")
+	}
+	if Sign("secret", 1600000001, []byte(`{"a":1}`)) == base {
+		t.Error("signature does not depend on the timestamp")
+	}
+	if Sign("secret", 1600000000, []byte(`{"a":2}`)) == base {
+		t.Error("signature does not depend on the body")
+	}
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"fmt"
+	"github.com/jackc/pgx"
+	log "github.com/sirupsen/logrus"
+	"github.com/valyala/fasthttp"
+	"strconv"
+	"sync"
+	"technopark-dbms/internal/pkg/constants"
+	"technopark-dbms/internal/pkg/webhook"
+	"time"
+)
+
+// claimQuery leases due deliveries to the dispatcher for as long as sending the whole batch may take,
+// skipping rows other instances hold, so a delivery whose dispatcher died is picked up again once the lease is over.
+// A delivery waits while an earlier one of its webhook waits for a retry or is leased, so every webhook gets events in order.
+const claimQuery = `with due as (
+    select id
+    from webhook_deliveries d
+    where status = 'pending'
+      and next_attempt <= now()
+      and webhook <> all ($3)
+      and not exists(select 1
+                     from webhook_deliveries e
+                     where e.webhook = d.webhook
+                       and e.status = 'pending'
+                       and e.id < d.id
+                       and e.next_attempt > now())
+    order by id
+    limit $1 for update skip locked
+), claimed as (
+    update webhook_deliveries d
+    set next_attempt = now() + $2::bigint * interval '1 millisecond'
+    from due
+    where d.id = due.id
+    returning d.id, d.webhook, d.event, d.attempts
+)
+select c.id, c.webhook, c.attempts, w.url, w.secret, o.type, o.created, o.payload::text
+from claimed c
+         join webhooks w on w.id = c.webhook
+         join outbox o on o.id = c.event
+order by c.id;`
+
+const (
+	deliveredQuery       = "update webhook_deliveries set status = $2, attempts = attempts + 1, last_error = null, response_status = $3, delivered = now() where id = $1;"
+	failedQuery          = "update webhook_deliveries set status = $2, attempts = $3, next_attempt = now() + $4::bigint * interval '1 millisecond', last_error = $5, response_status = $6 where id = $1;"
+	releaseQuery         = "update webhook_deliveries set next_attempt = now() where id = any($1);"
+	pruneDeliveriesQuery = "delete from webhook_deliveries where status <> 'pending' and created < $1;"
+	pruneOutboxQuery     = "delete from outbox o where created < $1 and not exists(select 1 from webhook_deliveries d where d.event = o.id);"
+)
+
+type claim struct {
+	id       int64
+	webhook  int64
+	attempts int32
+	url      string
+	secret   string
+	event    string
+	created  time.Time
+	payload  string
+}
+
+// Dispatcher sends due deliveries to webhook receivers and schedules retries of failed ones.
+// Every webhook has its deliveries sent in order by a goroutine of its own, so a slow receiver holds back only itself.
+// A failed delivery holds back the later ones of its webhook until it is delivered or dead.
+// Delivery is at least once: a receiver that acknowledges too late for the lease gets the event again.
+type Dispatcher struct {
+	DB     *pgx.ConnPool
+	Client *fasthttp.Client
+
+	mu   sync.Mutex
+	busy map[int64]bool
+}
+
+func NewDispatcher(db *pgx.ConnPool) *Dispatcher {
+	return &Dispatcher{
+		DB: db,
+		Client: &fasthttp.Client{
+			Name:         "technopark-dbms-webhooks",
+			ReadTimeout:  constants.WebhookTimeout,
+			WriteTimeout: constants.WebhookTimeout,
+		},
+		busy: make(map[int64]bool),
+	}
+}
+
+// Run delivers events until the process ends, it pauses only when nothing is due
+// or every goroutine it may start is busy. Webhooks being sent to are not claimed again until they are done.
+func (d *Dispatcher) Run() {
+	go d.prune()
+	for {
+		busy := d.busyWebhooks()
+		if len(busy) >= constants.WebhookConcurrency {
+			time.Sleep(constants.WebhookPollInterval)
+			continue
+		}
+		claims, err := d.claim(busy)
+		if err != nil {
+			log.WithError(err).Error("webhook claim error")
+		}
+		webhooks := make([]int64, 0)
+		groups := make(map[int64][]claim)
+		for _, c := range claims {
+			if _, ok := groups[c.webhook]; !ok {
+				webhooks = append(webhooks, c.webhook)
+			}
+			groups[c.webhook] = append(groups[c.webhook], c)
+		}
+		for _, id := range webhooks {
+			d.setBusy(id, true)
+			go func(id int64, claims []claim) {
+				defer d.setBusy(id, false)
+				for i, c := range claims {
+					if !d.deliver(c) {
+						d.release(claims[i+1:])
+						return
+					}
+				}
+			}(id, groups[id])
+		}
+		if len(claims) < constants.WebhookBatch {
+			time.Sleep(constants.WebhookPollInterval)
+		}
+	}
+}
+
+func (d *Dispatcher) busyWebhooks() []int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res := make([]int64, 0, len(d.busy))
+	for id := range d.busy {
+		res = append(res, id)
+	}
+	return res
+}
+
+func (d *Dispatcher) setBusy(webhook int64, busy bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if busy {
+		d.busy[webhook] = true
+	} else {
+		delete(d.busy, webhook)
+	}
+}
+
+// prune removes finished deliveries past WebhookRetention and then the events left without deliveries
+func (d *Dispatcher) prune() {
+	for range time.Tick(constants.WebhookPruneInterval) {
+		before := time.Now().Add(-constants.WebhookRetention)
+		if _, err := d.DB.Exec(pruneDeliveriesQuery, before); err != nil {
+			log.WithError(err).Error("webhook deliveries prune error")
+			continue
+		}
+		if _, err := d.DB.Exec(pruneOutboxQuery, before); err != nil {
+			log.WithError(err).Error("webhook outbox prune error")
+		}
+	}
+}
+
+// release gives up the leases of deliveries left after a failed one, so they are claimed again in order
+func (d *Dispatcher) release(claims []claim) {
+	if len(claims) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(claims))
+	for _, c := range claims {
+		ids = append(ids, c.id)
+	}
+	if _, err := d.DB.Exec(releaseQuery, ids); err != nil {
+		log.WithError(err).Error("webhook release error")
+	}
+}
+
+func (d *Dispatcher) claim(busy []int64) ([]claim, error) {
+	rows, err := d.DB.Query(claimQuery, constants.WebhookBatch, constants.WebhookTimeout.Milliseconds()*constants.WebhookBatch, busy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]claim, 0, constants.WebhookBatch)
+	for rows.Next() {
+		var c claim
+		if err = rows.Scan(&c.id, &c.webhook, &c.attempts, &c.url, &c.secret, &c.event, &c.created, &c.payload); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, rows.Err()
+}
+
+// deliver posts the event envelope signed with the webhook secret, any 2xx answer acknowledges it
+func (d *Dispatcher) deliver(c claim) bool {
+	body := []byte(fmt.Sprintf(`{"id":%d,"type":%q,"created":%q,"data":%s}`,
+		c.id, c.event, c.created.UTC().Format(time.RFC3339Nano), c.payload))
+	timestamp := time.Now().Unix()
+
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(c.url)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.SetContentType("application/json")
+	req.Header.Set(webhook.EventHeader, c.event)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatInt(c.id, 10))
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(c.secret, timestamp, body))
+	req.SetBody(body)
+
+	err := d.Client.DoTimeout(req, resp, constants.WebhookTimeout)
+	var responseStatus *int32
+	if err == nil {
+		status := int32(resp.StatusCode())
+		responseStatus = &status
+		if status >= 200 && status < 300 {
+			if _, err = d.DB.Exec(deliveredQuery, c.id, webhook.StatusDelivered, status); err != nil {
+				log.WithError(err).Error("webhook delivered mark error")
+			}
+			return true
+		}
+		err = fmt.Errorf("receiver answered %d", status)
+	}
+	d.fail(c, err, responseStatus)
+	return false
+}
+
+// fail schedules the next attempt with an exponential backoff, the delivery is dead after the last one
+func (d *Dispatcher) fail(c claim, cause error, responseStatus *int32) {
+	attempts := c.attempts + 1
+	status := webhook.StatusPending
+	if attempts >= constants.WebhookMaxAttempts {
+		status = webhook.StatusDead
+	}
+	_, err := d.DB.Exec(failedQuery, c.id, status, attempts, retryDelay(attempts).Milliseconds(), cause.Error(), responseStatus)
+	if err != nil {
+		log.WithError(err).Error("webhook failure mark error")
+	}
+}
+
+// retryShiftMax bounds the doublings of WebhookRetryBase, it is past WebhookRetryMax long before it could overflow
+const retryShiftMax = 20
+
+// retryDelay doubles WebhookRetryBase with every failed attempt up to WebhookRetryMax
+func retryDelay(attempts int32) time.Duration {
+	shift := attempts - 1
+	if shift < 0 {
+		shift = 0
+	} else if shift > retryShiftMax {
+		shift = retryShiftMax
+	}
+	if delay := constants.WebhookRetryBase << uint(shift); delay < constants.WebhookRetryMax {
+		return delay
+	}
+	return constants.WebhookRetryMax
+}
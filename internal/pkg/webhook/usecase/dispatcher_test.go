@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"technopark-dbms/internal/pkg/constants"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int32
+		want     time.Duration
+	}{
+		{-1, constants.WebhookRetryBase},
+		{0, constants.WebhookRetryBase},
+		{1, constants.WebhookRetryBase},
+		{2, 2 * constants.WebhookRetryBase},
+		{4, 8 * constants.WebhookRetryBase},
+		{9, 256 * constants.WebhookRetryBase},
+		{10, constants.WebhookRetryMax},
+		{33, constants.WebhookRetryMax},
+		{100, constants.WebhookRetryMax},
+		{1 << 30, constants.WebhookRetryMax},
+	}
+	for _, test := range tests {
+		if got := retryDelay(test.attempts); got != test.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
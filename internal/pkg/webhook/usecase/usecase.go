@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/go-openapi/strfmt"
+	"github.com/jackc/pgx"
+	"strconv"
+	"technopark-dbms/internal/pkg/domain"
+	"technopark-dbms/internal/pkg/utilities"
+	"technopark-dbms/internal/pkg/webhook"
+	"time"
+)
+
+const (
+	createWebhookQuery  = "insert into webhooks(url, secret, events) values ($1, $2, $3) returning id, active, created;"
+	getWebhooksQuery    = "select id, url, events, active, created from webhooks order by id;"
+	deleteWebhookQuery  = "delete from webhooks where id = $1;"
+	deliveryColumns     = "d.id, d.webhook, d.event, o.type, d.status, d.attempts, d.next_attempt, d.last_error, d.response_status, d.created, d.delivered"
+	replayDeliveryQuery = "update webhook_deliveries set status = $2, attempts = 0, next_attempt = now(), last_error = null where id = $1;"
+	getDeliveryQuery    = "select " + deliveryColumns + " from webhook_deliveries d join outbox o on o.id = d.event where d.id = $1;"
+	deliveriesScope     = "webhook_deliveries"
+)
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+type webhookUsecase struct {
+	DB *pgx.ConnPool
+}
+
+func NewWebhookUsecase(db *pgx.ConnPool) domain.WebhookUsecase {
+	return &webhookUsecase{
+		DB: db,
+	}
+}
+
+// CreateWebhook registers the webhook, a secret is generated when none is given.
+// The secret is only returned here, listings leave it out.
+func (u *webhookUsecase) CreateWebhook(w domain.Webhook) (*domain.Webhook, error) {
+	if w.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		w.Secret = hex.EncodeToString(secret)
+	}
+	var created time.Time
+	err := u.DB.QueryRow(createWebhookQuery, w.URL, w.Secret, w.Events).Scan(&w.ID, &w.Active, &created)
+	if err != nil {
+		return nil, err
+	}
+	w.Created = strfmt.DateTime(created)
+	return &w, nil
+}
+
+func (u *webhookUsecase) GetWebhooks() (domain.WebhookArray, error) {
+	rows, err := u.DB.Query(getWebhooksQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(domain.WebhookArray, 0)
+	for rows.Next() {
+		var w domain.Webhook
+		var created time.Time
+		if err = rows.Scan(&w.ID, &w.URL, &w.Events, &w.Active, &created); err != nil {
+			return nil, err
+		}
+		w.Created = strfmt.DateTime(created)
+		res = append(res, w)
+	}
+	return res, rows.Err()
+}
+
+// DeleteWebhook removes the webhook with its deliveries
+func (u *webhookUsecase) DeleteWebhook(id int64) error {
+	tag, err := u.DB.Exec(deleteWebhookQuery, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return webhook.NotFound
+	}
+	return nil
+}
+
+// GetDeliveries pages over deliveries of the webhook from the newest one, status filters them when it is set
+func (u *webhookUsecase) GetDeliveries(webhookID int64, status string, params utilities.ArrayOutParams) (domain.WebhookDeliveryArray, *utilities.Page, error) {
+	cursor, err := utilities.ParamsCursor(params, deliveriesScope, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	var exists bool
+	if err = u.DB.QueryRow("select exists(select 1 from webhooks where id = $1);", webhookID).Scan(&exists); err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, webhook.NotFound
+	}
+
+	// deliveries are listed from the newest one, desc is not offered
+	since, desc, backward := params.Since, true, false
+	if cursor != nil {
+		since, desc, backward = cursor.Key[0], cursor.Desc, cursor.Backward
+	}
+	req := psql.Select(deliveryColumns).
+		From("webhook_deliveries d").
+		Join("outbox o on o.id = d.event").
+		Where(sq.Eq{"d.webhook": webhookID})
+	if status != "" {
+		req = req.Where(sq.Eq{"d.status": status})
+	}
+	if since != "" {
+		sinceID, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return nil, nil, err
+		}
+		if desc != backward {
+			req = req.Where(sq.Lt{"d.id": sinceID})
+		} else {
+			req = req.Where(sq.Gt{"d.id": sinceID})
+		}
+	}
+	if desc != backward {
+		req = req.OrderBy("d.id desc")
+	} else {
+		req = req.OrderBy("d.id asc")
+	}
+	query, args, err := req.Limit(uint64(params.Limit)).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := u.DB.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	res := make(domain.WebhookDeliveryArray, 0)
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err = scanDelivery(rows, &d); err != nil {
+			return nil, nil, err
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, nil, rows.Err()
+	}
+
+	if backward {
+		for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+			res[i], res[j] = res[j], res[i]
+		}
+	}
+	page := &utilities.Page{}
+	if len(res) != 0 {
+		page = utilities.NewPage(deliveriesScope, desc, cursor, len(res), params.Limit,
+			[]string{strconv.FormatInt(res[0].ID, 10)}, []string{strconv.FormatInt(res[len(res)-1].ID, 10)})
+	}
+	return res, page, nil
+}
+
+// ReplayDelivery sends the delivery again from the first attempt, dead and delivered ones included
+func (u *webhookUsecase) ReplayDelivery(id int64) (*domain.WebhookDelivery, error) {
+	tag, err := u.DB.Exec(replayDeliveryQuery, id, webhook.StatusPending)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, webhook.DeliveryNotFound
+	}
+	d := &domain.WebhookDelivery{}
+	if err = scanDelivery(u.DB.QueryRow(getDeliveryQuery, id), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+type row interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(r row, d *domain.WebhookDelivery) error {
+	var nextAttempt, created time.Time
+	var delivered *time.Time
+	var lastError *string
+	var responseStatus *int32
+	err := r.Scan(&d.ID, &d.Webhook, &d.Event, &d.Type, &d.Status, &d.Attempts, &nextAttempt, &lastError, &responseStatus, &created, &delivered)
+	if err != nil {
+		return err
+	}
+	d.NextAttempt, d.Created = strfmt.DateTime(nextAttempt), strfmt.DateTime(created)
+	if delivered != nil {
+		deliveredAt := strfmt.DateTime(*delivered)
+		d.Delivered = &deliveredAt
+	}
+	if lastError != nil {
+		d.LastError = *lastError
+	}
+	if responseStatus != nil {
+		d.ResponseStatus = *responseStatus
+	}
+	return nil
+}